@@ -0,0 +1,97 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const waitPollingInterval = 10 * time.Millisecond
+
+// InvocationCount returns the number of invocations of the mock till now.
+// It can be passed to gomega's Eventually and Consistently as mock.InvocationCount
+func (mock *Mock) InvocationCount() int {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	return len(mock.invocations)
+}
+
+// WaitForInvocations waits until the mock has been invoked at least n times, and returns its invocations.
+// It fails if that doesn't happen within the timeout
+func (mock *Mock) WaitForInvocations(n int, timeout time.Duration) []Invocation {
+	deadline := time.Now().Add(timeout)
+	for {
+		invocations := mock.Invocations()
+		if len(invocations) >= n {
+			return invocations
+		}
+		if time.Now().After(deadline) {
+			mock.failHandler(fmt.Sprintf("Expected %s to be invoked %d times within %v, but it was invoked %d times", mock.displayName(), n, timeout, len(invocations)))
+			return invocations
+		}
+		time.Sleep(waitPollingInterval)
+	}
+}
+
+// InvocationsChan returns a channel that delivers the invocations of the mock as they happen,
+// starting with the invocations that already happened. Receiving from it never slows down the mock.
+// The returned function stops the delivery and closes the channel, dropping the invocations not yet received
+func (mock *Mock) InvocationsChan() (<-chan Invocation, func()) {
+	incoming := make(chan Invocation)
+	outgoing := make(chan Invocation)
+	done := make(chan struct{})
+
+	listener := &listener{notify: func(invocation Invocation) {
+		incoming <- invocation
+	}}
+	mock.lock.Lock()
+	queue := append([]Invocation{}, mock.invocations...)
+	mock.listeners = append(mock.listeners, listener)
+	mock.lock.Unlock()
+
+	go func() {
+		defer close(outgoing)
+		for {
+			var next Invocation
+			var send chan Invocation
+			if len(queue) > 0 {
+				next = queue[0]
+				send = outgoing
+			}
+
+			select {
+			case invocation := <-incoming:
+				queue = append(queue, invocation)
+			case send <- next:
+				queue = queue[1:]
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			mock.removeListener(listener)
+			close(done)
+		})
+	}
+	return outgoing, stop
+}
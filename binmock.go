@@ -18,21 +18,77 @@ package binmock
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
 	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 //go:generate go-bindata -pkg binmock -o packaged_client.go client/
 type Mock struct {
+	lock sync.Mutex
+
 	Path                string
+	name                string
 	identifier          string
 	currentMappingIndex int
+	calls               int
 	failHandler         FailHandler
+	unmocked            bool
+	policy              *policy
+	redactions          *redactions
+	captures            *captures
 
 	mappings    []*InvocationStub
 	invocations []Invocation
+	running     []heldInvocation
+	processes   map[int]process
+	listeners   []*listener
+	installed   []string
+	recorder    *recorder
+	spyOn       string
+	pending     map[int]int
+	learning    *InvocationStub
+	unmatched   []int
+	otherwise   *InvocationStub
+
+	unexpectedCallPolicy UnexpectedCallPolicy
+	matchingMode         MatchingMode
+}
+
+// MatchingMode is how a mock picks the stub for an invocation
+type MatchingMode int
+
+const (
+	// MatchInOrder uses the stubs one after the other, in the order they were declared
+	MatchInOrder MatchingMode = iota
+	// MatchByPrecedence uses the matching stub with the highest priority and, among those, the most recently declared one.
+	// Stubs keep answering until replaced, except for sequences that fail when exhausted
+	MatchByPrecedence
+)
+
+// UnexpectedCallPolicy is what a mock does when invoked in a way that matches no stub
+type UnexpectedCallPolicy struct {
+	fail     bool
+	exitCode int
+}
+
+var (
+	// FailOnUnexpectedCall invokes the fail handler, and the mock exits with 1
+	FailOnUnexpectedCall = UnexpectedCallPolicy{fail: true, exitCode: 1}
+	// RespondSilently records the invocation, and the mock exits with 0 without any output
+	RespondSilently = UnexpectedCallPolicy{}
+)
+
+// FailAndExitWith invokes the fail handler, and the mock exits with exitCode
+func FailAndExitWith(exitCode int) UnexpectedCallPolicy {
+	return UnexpectedCallPolicy{fail: true, exitCode: exitCode}
 }
 
 // The type of the function that will be invoked when an assertion fails. Compatible with the ginkgo fail handler (`ginkgo.Fail`)
@@ -40,33 +96,293 @@ type FailHandler func(message string, callerSkip ...int)
 
 // Creates a new binary mock
 func NewBinMock(failHandler FailHandler) *Mock {
+	return newBinMock("", failHandler)
+}
+
+// Creates a new binary mock whose executable is called name, in a directory of its own.
+// The name is used in all the failure messages of the mock
+func NewNamedBinMock(name string, failHandler FailHandler) *Mock {
+	if name == "" || strings.ContainsRune(name, os.PathSeparator) {
+		failHandler(fmt.Sprintf("invalid mock name %q", name))
+		return nil
+	}
+	return newBinMock(name, failHandler)
+}
+
+// Creates a new spy, a binary mock that runs the real executable with the same arguments, environment and standard input,
+// passing its output through. Stubs set up on the spy override the calls they match, each of them once
+func NewSpy(realPath string, failHandler FailHandler) *Mock {
+	mock := newBinMock("", failHandler)
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.spyOn = realPath
+	return mock
+}
+
+func newBinMock(name string, failHandler FailHandler) *Mock {
 	server := getCurrentServer()
 
 	identifier := strconv.FormatInt(time.Now().UnixNano(), 10)
-	binaryPath, err := buildBinary(identifier, server.listener.Addr().String())
+	binaryPath, err := buildBinary(identifier, server.listener.Addr().String(), name)
 	if err != nil {
-		failHandler(fmt.Sprintf("cant build binary %v", err))
+		failHandler(fmt.Sprintf("cant build binary %s %v", name, err))
 	}
 
-	mock := &Mock{identifier: identifier, Path: binaryPath, failHandler: failHandler}
+	mock := &Mock{identifier: identifier, name: name, Path: binaryPath, failHandler: failHandler, policy: &policy{}, redactions: &redactions{}, captures: &captures{}, unexpectedCallPolicy: FailOnUnexpectedCall}
 
 	server.monitor(mock)
 	return mock
 }
 
-func (mock *Mock) invoke(args, env, stdin []string) (int, string, string) {
-	if mock.currentMappingIndex >= len(mock.mappings) {
-		mock.failHandler(fmt.Sprintf("Too many calls to the mock! Last call with %v", args))
-		return 1, "", ""
+func (mock *Mock) invoke(request invocationRequest) invocationResponse {
+	captures := mock.activeCaptures()
+	request.files = captures.capture(request.Dir, request.Args)
+	request.trees = captures.captureTrees(request.Dir)
+	response := mock.answer(request)
+	if response.gate != nil {
+		response.gate.pass(response.killed)
+		mock.finish(response.sequence)
+	}
+	return response
+}
+
+func (mock *Mock) answer(request invocationRequest) invocationResponse {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	args := request.Args
+	mock.calls = mock.calls + 1
+	parsedEnv := parseEnv(request.Env)
+	mock.started(request.Pid, args, parsedEnv)
+	defer func() {
+		// a fail handler that panics, as ginkgo.Fail does, leaves the process without a response, so it can't report its exit
+		if recovered := recover(); recovered != nil {
+			delete(mock.processes, request.Pid)
+			panic(recovered)
+		}
+	}()
+	if mock.unmocked {
+		mock.fail(fmt.Sprintf("Unexpected call to %s, which is not mocked! Call with %v", filepath.Base(request.Argv0), args), parsedEnv)
+		return newInvocationResponse(127, "", "")
+	}
+	if violations := mock.activePolicy().violations(mock.displayName(), args, parsedEnv); len(violations) > 0 {
+		mock.fail(strings.Join(violations, "\n"), parsedEnv)
+		return newInvocationResponse(1, "", "")
+	}
+	if mock.recorder != nil {
+		response := mock.passthrough(mock.recorder.cassette.Executable, request)
+		mock.recorder.record(response.InvocationId, mock.invocations[len(mock.invocations)-1])
+		return response
+	}
+	if mock.spyOn != "" {
+		if stub := mock.takeOverride(request); stub != nil {
+			return mock.respond(request, stub)
+		}
+		return mock.passthrough(mock.spyOn, request)
+	}
+	if mock.learning != nil {
+		return mock.learn(request)
+	}
+	if response, matched := mock.respondWithNextStub(request); matched {
+		return response
+	}
+	if mock.otherwise != nil {
+		return mock.respond(request, mock.otherwise)
+	}
+	return mock.unexpected(request, parsedEnv)
+}
+
+func (mock *Mock) unexpected(request invocationRequest, env map[string]string) invocationResponse {
+	if !mock.unexpectedCallPolicy.fail {
+		return mock.respond(request, &InvocationStub{})
 	}
+
+	args := request.Args
+	message := ""
+	if mock.matchingMode == MatchByPrecedence {
+		message = mock.mismatchReport(fmt.Sprintf("No stub of %s matches the call with %v", mock.displayName(), args), args, env, nil)
+	} else if mock.currentMappingIndex >= len(mock.mappings) {
+		message = mock.mismatchReport(fmt.Sprintf("Too many calls to %s! Last call with %v", mock.displayName(), args), args, env, nil)
+	} else {
+		currentMapping := mock.mappings[mock.currentMappingIndex]
+		mock.currentMappingIndex = mock.currentMappingIndex + 1
+		if currentMapping.expectedArgs != nil && !reflect.DeepEqual(currentMapping.expectedArgs, args) {
+			message = mock.mismatchReport(fmt.Sprintf("Expected %v to equal %v in the call to %s", args, currentMapping.expectedArgs, mock.displayName()), args, env, currentMapping)
+		} else {
+			message = mock.mismatchReport(fmt.Sprintf("Expected %s to be invoked as %s but it was invoked as %s", mock.displayName(), currentMapping.invokedAs, filepath.Base(request.Argv0)), args, env, currentMapping)
+		}
+	}
+	mock.fail(message, env)
+	return newInvocationResponse(mock.unexpectedCallPolicy.exitCode, "", "")
+}
+
+func (mock *Mock) respondWithNextStub(request invocationRequest) (invocationResponse, bool) {
+	if mock.matchingMode == MatchByPrecedence {
+		return mock.respondByPrecedence(request)
+	}
+	if mock.currentMappingIndex+1 < len(mock.mappings) && mock.mappings[mock.currentMappingIndex].exhausted() && mock.mappings[mock.currentMappingIndex+1].matches(request) {
+		mock.currentMappingIndex = mock.currentMappingIndex + 1
+	}
+	if mock.currentMappingIndex >= len(mock.mappings) || !mock.mappings[mock.currentMappingIndex].matches(request) {
+		return invocationResponse{}, false
+	}
+
 	currentMapping := mock.mappings[mock.currentMappingIndex]
-	mock.currentMappingIndex = mock.currentMappingIndex + 1
-	if currentMapping.expectedArgs != nil && !reflect.DeepEqual(currentMapping.expectedArgs, args) {
-		mock.failHandler(fmt.Sprintf("Expected %v to equal %v", args, currentMapping.expectedArgs))
-		return 1, "", ""
+	response := mock.respond(request, currentMapping)
+	if currentMapping.usedUp() {
+		mock.currentMappingIndex = mock.currentMappingIndex + 1
+	}
+	return response, true
+}
+
+func (mock *Mock) respondByPrecedence(request invocationRequest) (invocationResponse, bool) {
+	var selected *InvocationStub
+	for i := len(mock.mappings) - 1; i >= 0; i-- {
+		stub := mock.mappings[i]
+		if !stub.matches(request) || !stub.selectableByPrecedence() {
+			continue
+		}
+		if selected == nil || stub.priority > selected.priority {
+			selected = stub
+		}
+	}
+	if selected == nil {
+		return invocationResponse{}, false
+	}
+
+	if len(selected.responses()) == 1 {
+		// single responses keep answering until replaced by another stub
+		selected.served = 0
+	}
+	return mock.respond(request, selected), true
+}
+
+func (mock *Mock) respond(request invocationRequest, stub *InvocationStub) invocationResponse {
+	orderingLock.Lock()
+	response, failure := mock.respondInOrder(request, stub)
+	orderingLock.Unlock()
+
+	if failure != "" {
+		mock.fail(failure, parseEnv(request.Env))
+	}
+	return response
+}
+
+func (mock *Mock) respondInOrder(request invocationRequest, stub *InvocationStub) (invocationResponse, string) {
+	head := stub.first()
+	if violation := head.orderViolation(); violation != "" {
+		return newInvocationResponse(1, "", ""), fmt.Sprintf("%s Call with %v", violation, request.Args)
+	}
+	stub, ok := stub.take()
+	if !ok {
+		return newInvocationResponse(1, "", ""), fmt.Sprintf("All the responses of the stub of %s are used up! Last call with %v", mock.displayName(), request.Args)
+	}
+	effects, err := stub.fileEffects(request.Args)
+	if err != nil {
+		return newInvocationResponse(1, "", ""), fmt.Sprintf("Cant set up the side effects of the call to %s with %v: %v", mock.displayName(), request.Args, err)
+	}
+	response := newInvocationResponse(stub.exitCode, stub.stdout, stub.stderr)
+	response.Effects = effects
+	invocation := mock.record(request, response)
+	head.answeredAt = append(head.answeredAt, invocation.sequence)
+	if stub.gate != nil {
+		response.gate = stub.gate
+		response.sequence = invocation.sequence
+		response.killed = make(chan struct{})
+		mock.running = append(mock.running, heldInvocation{invocation: invocation, killed: response.killed})
+	}
+	return response, ""
+}
+
+func (mock *Mock) finish(sequence uint64) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	for i, held := range mock.running {
+		if held.invocation.sequence == sequence {
+			mock.running = append(mock.running[:i], mock.running[i+1:]...)
+			return
+		}
+	}
+}
+
+func (mock *Mock) passthrough(executable string, request invocationRequest) invocationResponse {
+	response := invocationResponse{Passthrough: executable, InvocationId: mock.calls}
+	mock.record(request, response)
+	if mock.pending == nil {
+		mock.pending = map[int]int{}
+	}
+	mock.pending[response.InvocationId] = len(mock.invocations) - 1
+	return response
+}
+
+func (mock *Mock) record(request invocationRequest, response invocationResponse) Invocation {
+	invocation := newInvocation(request.Args, request.Env, request.Stdin, request.Streams)
+	invocation.sequence = atomic.AddUint64(&invocationSequence, 1)
+	invocation.argv0 = request.Argv0
+	invocation.pid = request.Pid
+	invocation.dir = request.Dir
+	invocation.files = request.files
+	invocation.trees = request.trees
+	invocation.exitCode = response.ExitCode
+	invocation.stdout = response.Stdout
+	invocation.stderr = response.Stderr
+	invocation.redactions = mock.activeRedactions()
+	mock.invocations = append(mock.invocations, invocation)
+	for _, listener := range mock.listeners {
+		listener.notify(invocation)
+	}
+	return invocation
+}
+
+func (mock *Mock) complete(result invocationResult) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	if index, found := mock.pending[result.InvocationId]; found && index < len(mock.invocations) {
+		delete(mock.pending, result.InvocationId)
+		mock.invocations[index].exitCode = result.ExitCode
+		mock.invocations[index].stdout = result.Stdout
+		mock.invocations[index].stderr = result.Stderr
+	}
+	if mock.recorder != nil {
+		if err := mock.recorder.complete(result); err != nil {
+			mock.failHandler(fmt.Sprintf("cant record the invocation %v", err))
+		}
+	}
+}
+
+func (mock *Mock) takeOverride(request invocationRequest) *InvocationStub {
+	for i, stub := range mock.mappings {
+		if stub.matches(request) {
+			if len(stub.responses())-stub.first().served <= 1 && stub.first().whenExhausted == FailWhenExhausted {
+				mock.mappings = append(mock.mappings[:i], mock.mappings[i+1:]...)
+			}
+			return stub
+		}
+	}
+	return nil
+}
+
+func (mock *Mock) displayName() string {
+	if mock.name == "" {
+		return "the mock"
 	}
-	mock.invocations = append(mock.invocations, newInvocation(args, env, stdin))
-	return currentMapping.exitCode, currentMapping.stdout, currentMapping.stderr
+	return "the mock " + mock.name
+}
+
+func (mock *Mock) fail(message string, env map[string]string) {
+	mock.failHandler(mock.activeRedactions().redact(message, env))
+}
+
+func (mock *Mock) activePolicy() *policy {
+	globalPolicyLock.Lock()
+	defer globalPolicyLock.Unlock()
+	return mergePolicies(globalPolicy, mock.policy)
+}
+
+func (mock *Mock) activeRedactions() *redactions {
+	forbiddenInArgs := &redactions{values: mock.activePolicy().forbiddenInArgs}
+	globalRedactionsLock.Lock()
+	defer globalRedactionsLock.Unlock()
+	return mergeRedactions(globalRedactions, mock.redactions, forbiddenInArgs)
 }
 
 // Sets up a stub for a possible invocation of the mock, accepting any arguments
@@ -82,19 +398,174 @@ func (mock *Mock) WhenCalledWith(args ...string) *InvocationStub {
 	return mock.createMapping(invocation)
 }
 
+// Otherwise sets up a stub for any invocation of the mock that doesn't match the next stub, e.g. to always answer to --version.
+// Invocations answered by it don't use up the other stubs
+func (mock *Mock) Otherwise() *InvocationStub {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.otherwise = &InvocationStub{whenExhausted: RepeatLastWhenExhausted, mock: mock}
+	return mock.otherwise
+}
+
+// MatchStubs sets how the mock picks the stub for an invocation. It uses the stubs in order by default
+func (mock *Mock) MatchStubs(mode MatchingMode) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.matchingMode = mode
+	return mock
+}
+
+// OnUnexpectedCall sets what the mock does when invoked in a way that matches no stub. It fails by default
+func (mock *Mock) OnUnexpectedCall(policy UnexpectedCallPolicy) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.unexpectedCallPolicy = policy
+	return mock
+}
+
+// ForbidInArgs fails any invocation of the mock that receives one of the secrets as part of its arguments
+func (mock *Mock) ForbidInArgs(secrets ...string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.policy.forbidInArgs(secrets...)
+	return mock
+}
+
+// RequireInEnv fails any invocation of the mock that doesn't have all of the environment variables set
+func (mock *Mock) RequireInEnv(names ...string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.policy.requireInEnv(names...)
+	return mock
+}
+
+// RedactValues hides the values from all the messages produced by the mock and from its invocation dumps
+func (mock *Mock) RedactValues(values ...string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.redactions.redactValues(values...)
+	return mock
+}
+
+// RedactEnv hides the values of the environment variables, as set at the time of each invocation,
+// from all the messages produced by the mock and from its invocation dumps
+func (mock *Mock) RedactEnv(names ...string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.redactions.redactEnv(names...)
+	return mock
+}
+
+// RedactPatterns hides anything matching the patterns from all the messages produced by the mock and from its invocation dumps
+func (mock *Mock) RedactPatterns(patterns ...*regexp.Regexp) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.redactions.redactPatterns(patterns...)
+	return mock
+}
+
+// LinkAs creates symbolic links to the mock in dir, one for each of the names.
+// All the links are routed to the mock, and the name used is available as Invocation.InvokedAs
+func (mock *Mock) LinkAs(dir string, names ...string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	for _, name := range names {
+		link := filepath.Join(dir, name)
+		if err := os.Symlink(mock.Path, link); err != nil {
+			mock.failHandler(fmt.Sprintf("cant link %s as %s %v", mock.Path, name, err))
+			continue
+		}
+		mock.installed = append(mock.installed, link)
+	}
+	return mock
+}
+
+// InstallAt copies the mock to path, creating any missing parent directories.
+// Invocations of the copy are routed to the mock
+func (mock *Mock) InstallAt(path string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		mock.failHandler(fmt.Sprintf("cant create the directory of %s %v", path, err))
+		return mock
+	}
+	if err := copyExecutable(mock.Path, path); err != nil {
+		mock.failHandler(fmt.Sprintf("cant install the mock at %s %v", path, err))
+		return mock
+	}
+	mock.installed = append(mock.installed, path)
+	return mock
+}
+
+// Cleanup removes the copies and links of the mock created with InstallAt and LinkAs
+func (mock *Mock) Cleanup() {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	for _, path := range mock.installed {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			mock.failHandler(fmt.Sprintf("cant remove %s %v", path, err))
+		}
+	}
+	mock.installed = nil
+}
+
+type listener struct {
+	notify func(Invocation)
+}
+
+func (mock *Mock) onInvocation(notify func(Invocation)) *listener {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	listener := &listener{notify: notify}
+	mock.listeners = append(mock.listeners, listener)
+	return listener
+}
+
+func (mock *Mock) removeListener(listener *listener) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	for i, current := range mock.listeners {
+		if current == listener {
+			mock.listeners = append(mock.listeners[:i], mock.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
 func (mock *Mock) createMapping(mapping *InvocationStub) *InvocationStub {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mapping.mock = mock
 	mock.mappings = append(mock.mappings, mapping)
 	return mapping
 }
 
 // Invocations returns the list of invocations of the mock till now
 func (mock *Mock) Invocations() []Invocation {
-	return mock.invocations
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	return append([]Invocation{}, mock.invocations...)
+}
+
+// RunningInvocations returns the invocations of the mock that are held by a gate, see InvocationStub.BlockUntilReleased
+func (mock *Mock) RunningInvocations() []Invocation {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	running := []Invocation{}
+	for _, held := range mock.running {
+		running = append(running, held.invocation)
+	}
+	return running
 }
 
 // Resets the mapping and invocations to the mock
 func (mock *Mock) Reset() {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
 	mock.mappings = []*InvocationStub{}
 	mock.invocations = []Invocation{}
 	mock.currentMappingIndex = 0
+	mock.calls = 0
+	mock.pending = nil
+	mock.unmatched = nil
 }
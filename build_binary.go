@@ -25,13 +25,17 @@ import (
 	"path/filepath"
 )
 
-func buildBinary(identifier, serverUrl string) (string, error) {
+func buildBinary(identifier, serverUrl, name string) (string, error) {
 	clientPath, err := getSourceFile()
 	if err != nil {
 		return "", fmt.Errorf("cant extract client source %v", err)
 	}
 
-	binaryPath, err := doBuild(clientPath, "-ldflags", "-X main.serverUrl="+serverUrl+" -X main.identifier="+identifier)
+	if name == "" {
+		name = path.Base(clientPath)
+	}
+
+	binaryPath, err := doBuild(clientPath, name, "-ldflags", "-X main.serverUrl="+serverUrl+" -X main.identifier="+identifier)
 
 	if err != nil {
 		return "", fmt.Errorf("can't build binary %v", err)
@@ -72,13 +76,13 @@ func getSourceFile() (string, error) {
 	return sourceFilePath, nil
 }
 
-func doBuild(packagePath string, args ...string) (compiledPath string, err error) {
+func doBuild(packagePath, executableName string, args ...string) (compiledPath string, err error) {
 	tmpDir, err := ioutil.TempDir("", "bin_mock")
 	if err != nil {
 		return "", err
 	}
 
-	executable := filepath.Join(tmpDir, path.Base(packagePath))
+	executable := filepath.Join(tmpDir, executableName)
 	cmdArgs := append([]string{"build"}, args...)
 	cmdArgs = append(cmdArgs, "-o", executable, packagePath)
 
@@ -91,3 +95,11 @@ func doBuild(packagePath string, args ...string) (compiledPath string, err error
 
 	return executable, nil
 }
+
+func copyExecutable(source, destination string) error {
+	data, err := ioutil.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(destination, data, 0755)
+}
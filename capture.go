@@ -0,0 +1,166 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type captures struct {
+	argIndices []int
+	patterns   []string
+	trees      []treeCapture
+}
+
+type treeCapture struct {
+	dir    string
+	hashes bool
+}
+
+// FileEntry is a file or directory in a captured directory tree, see Mock.CaptureDirTree
+type FileEntry struct {
+	// Path is relative to the captured directory, with forward slashes
+	Path string
+	Size int64
+	Mode os.FileMode
+	// Hash is the hex encoded SHA-256 of the contents of regular files, when captured with Mock.CaptureDirTreeWithHashes
+	Hash string
+}
+
+// CaptureFilesAtArgs captures, at the time of each invocation, the contents of the files passed as the arguments at the indexes,
+// e.g. 1 for `--config tmp.yml`. Arguments that aren't files are ignored. See Invocation.CapturedFile
+func (mock *Mock) CaptureFilesAtArgs(indices ...int) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.captures.argIndices = append(mock.captures.argIndices, indices...)
+	return mock
+}
+
+// CaptureFiles captures, at the time of each invocation, the contents of the files matching the glob patterns.
+// Relative patterns are resolved in the working directory of the mock process. See Invocation.CapturedFile
+func (mock *Mock) CaptureFiles(patterns ...string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.captures.patterns = append(mock.captures.patterns, patterns...)
+	return mock
+}
+
+// CaptureDirTree captures, at the time of each invocation, the paths, sizes and modes of everything in the directories.
+// Relative directories are resolved in the working directory of the mock process, which is captured when none are given.
+// See Invocation.DirTree
+func (mock *Mock) CaptureDirTree(dirs ...string) *Mock {
+	return mock.captureDirTree(false, dirs)
+}
+
+// CaptureDirTreeWithHashes is like CaptureDirTree, also capturing the hashes of the contents of the files
+func (mock *Mock) CaptureDirTreeWithHashes(dirs ...string) *Mock {
+	return mock.captureDirTree(true, dirs)
+}
+
+func (mock *Mock) captureDirTree(hashes bool, dirs []string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	for _, dir := range dirs {
+		mock.captures.trees = append(mock.captures.trees, treeCapture{dir: dir, hashes: hashes})
+	}
+	return mock
+}
+
+func (captures *captures) captureTrees(dir string) map[string][]FileEntry {
+	trees := map[string][]FileEntry{}
+	for _, tree := range captures.trees {
+		trees[tree.dir] = captureTree(resolve(dir, tree.dir), tree.hashes)
+	}
+	return trees
+}
+
+func captureTree(root string, hashes bool) []FileEntry {
+	var entries []FileEntry
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == root {
+			return nil
+		}
+		relative, _ := filepath.Rel(root, path)
+		entry := FileEntry{Path: filepath.ToSlash(relative), Size: info.Size(), Mode: info.Mode()}
+		if hashes && info.Mode().IsRegular() {
+			entry.Hash = hashFile(path)
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries
+}
+
+func hashFile(path string) string {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(contents)
+	return hex.EncodeToString(hash[:])
+}
+
+// activeCaptures copies what the mock captures, so that the files can be read without holding any lock
+func (mock *Mock) activeCaptures() captures {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	return captures{
+		argIndices: append([]int{}, mock.captures.argIndices...),
+		patterns:   append([]string{}, mock.captures.patterns...),
+		trees:      append([]treeCapture{}, mock.captures.trees...),
+	}
+}
+
+func (captures *captures) capture(dir string, args []string) map[string]string {
+	files := map[string]string{}
+	for _, index := range captures.argIndices {
+		if index >= 0 && index < len(args) {
+			captureFile(files, dir, args[index])
+		}
+	}
+	for _, pattern := range captures.patterns {
+		matches, _ := filepath.Glob(resolve(dir, pattern))
+		for _, match := range matches {
+			if !filepath.IsAbs(pattern) {
+				match = strings.TrimPrefix(match, dir+string(filepath.Separator))
+			}
+			captureFile(files, dir, match)
+		}
+	}
+	return files
+}
+
+func captureFile(files map[string]string, dir, path string) {
+	if contents, err := ioutil.ReadFile(resolve(dir, path)); err == nil {
+		files[path] = string(contents)
+	}
+}
+
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
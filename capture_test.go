@@ -0,0 +1,155 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/pivotal-cf/go-binmock"
+)
+
+var _ = Describe("Captured files", func() {
+	var binMock *binmock.Mock
+	var currentMockFailure *mockFailure
+	var workDir string
+
+	BeforeEach(func() {
+		currentMockFailure = &mockFailure{}
+		binMock = binmock.NewBinMock(currentMockFailure.Fail)
+		binMock.WhenCalled().WillRemoveFile("tmp.yml")
+
+		var err error
+		workDir, err = ioutil.TempDir("", "binmock-capture")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(workDir, "tmp.yml"), []byte("key: value"), 0644)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(workDir)
+	})
+
+	runIn := func(dir string, args ...string) binmock.Invocation {
+		command := MakeCommand(binMock.Path, args...)
+		command.Dir = dir
+		StartCommand(command)
+		return binMock.Invocations()[0]
+	}
+
+	It("captures the files passed as arguments, before they are removed", func() {
+		binMock.CaptureFilesAtArgs(1)
+
+		invocation := runIn(workDir, "--config", "tmp.yml")
+
+		Expect(invocation.CapturedFile("tmp.yml")).To(Equal("key: value"))
+		Expect(filepath.Join(workDir, "tmp.yml")).NotTo(BeAnExistingFile())
+		Expect(invocation.Dir()).To(Equal(workDir))
+	})
+
+	It("ignores arguments that aren't files", func() {
+		binMock.CaptureFilesAtArgs(0, 5)
+
+		invocation := runIn(workDir, "--config", "tmp.yml")
+
+		Expect(invocation.CapturedFiles()).NotTo(HaveKey("--config"))
+	})
+
+	It("captures the files matching the patterns", func() {
+		Expect(ioutil.WriteFile(filepath.Join(workDir, "other.yml"), []byte("other: value"), 0644)).To(Succeed())
+		binMock.CaptureFiles("*.yml", filepath.Join(workDir, "other.yml"))
+
+		invocation := runIn(workDir)
+
+		Expect(invocation.CapturedFile("tmp.yml")).To(Equal("key: value"))
+		Expect(invocation.CapturedFile("other.yml")).To(Equal("other: value"))
+		Expect(invocation.CapturedFile(filepath.Join(workDir, "other.yml"))).To(Equal("other: value"))
+	})
+
+	It("doesn't hold up other mocks while reading the files", func() {
+		fifo := filepath.Join(workDir, "fifo")
+		Expect(syscall.Mkfifo(fifo, 0600)).To(Succeed())
+		binMock.CaptureFiles("fifo")
+		command := MakeCommand(binMock.Path)
+		command.Dir = workDir
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		otherMock := binmock.NewBinMock(currentMockFailure.Fail)
+		otherMock.WhenCalled()
+		RunCommand(otherMock.Path)
+		Consistently(session, 200*time.Millisecond).ShouldNot(gexec.Exit())
+
+		Expect(ioutil.WriteFile(fifo, []byte("unblocked"), 0600)).To(Succeed())
+		Eventually(session, 10*time.Second).Should(gexec.Exit(0))
+		Expect(binMock.Invocations()[0].CapturedFile("fifo")).To(Equal("unblocked"))
+	})
+
+	It("doesn't capture files by default", func() {
+		invocation := runIn(workDir, "--config", "tmp.yml")
+
+		Expect(invocation.CapturedFiles()).To(BeEmpty())
+	})
+
+	Describe("directory trees", func() {
+		BeforeEach(func() {
+			Expect(os.Mkdir(filepath.Join(workDir, "sub"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(workDir, "sub", "a.txt"), []byte("a"), 0600)).To(Succeed())
+		})
+
+		It("captures the working directory, before the files are removed", func() {
+			binMock.CaptureDirTree()
+
+			invocation := runIn(workDir)
+
+			Expect(invocation.DirTree(".")).To(Equal([]binmock.FileEntry{
+				{Path: "sub", Size: dirSize(filepath.Join(workDir, "sub")), Mode: os.ModeDir | 0755},
+				{Path: "sub/a.txt", Size: 1, Mode: 0600},
+				{Path: "tmp.yml", Size: 10, Mode: 0644},
+			}))
+		})
+
+		It("captures the given directories, with hashes", func() {
+			binMock.CaptureDirTreeWithHashes("sub")
+
+			invocation := runIn(workDir)
+
+			Expect(invocation.DirTree("sub")).To(Equal([]binmock.FileEntry{
+				{Path: "a.txt", Size: 1, Mode: 0600, Hash: "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"},
+			}))
+			Expect(invocation.DirTree(".")).To(BeEmpty())
+		})
+
+		It("doesn't capture directories by default", func() {
+			invocation := runIn(workDir)
+
+			Expect(invocation.DirTree(".")).To(BeEmpty())
+		})
+	})
+})
+
+func dirSize(path string) int64 {
+	info, err := os.Stat(path)
+	Expect(err).NotTo(HaveOccurred())
+	return info.Size()
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+// Cassette is a recording of the interactions of a mock with a real executable
+type Cassette struct {
+	Executable   string
+	Interactions []Interaction
+}
+
+// Interaction is a recorded invocation of an executable together with its outcome
+type Interaction struct {
+	Args     []string
+	Env      map[string]string
+	Stdin    []string
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// LoadCassette reads a cassette from a JSON file
+func LoadCassette(path string) (Cassette, error) {
+	cassette := Cassette{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cassette, err
+	}
+	err = json.Unmarshal(data, &cassette)
+	return cassette, err
+}
+
+// Save writes the cassette to a JSON file
+func (cassette Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+type recorder struct {
+	cassettePath string
+	envKeys      []string
+	cassette     Cassette
+	pending      map[int]Interaction
+	// completed holds the invocation ids of the interactions in the cassette, which are kept in invocation order
+	completed []int
+}
+
+// Record makes the mock forward every invocation to the real executable, and save the interactions to a cassette file.
+// Only the environment variables named in envKeys are saved. Stubs are ignored while recording
+func (mock *Mock) Record(executable, cassettePath string, envKeys ...string) *Mock {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.recorder = &recorder{
+		cassettePath: cassettePath,
+		envKeys:      envKeys,
+		cassette:     Cassette{Executable: executable},
+		pending:      map[int]Interaction{},
+	}
+	return mock
+}
+
+// Replay sets up a stub for each of the interactions saved in the cassette file, in order.
+// The stubs match on the arguments only: the recorded environment and standard input are ignored
+func (mock *Mock) Replay(cassettePath string) *Mock {
+	cassette, err := LoadCassette(cassettePath)
+	if err != nil {
+		mock.failHandler(fmt.Sprintf("cant load cassette %s %v", cassettePath, err))
+		return mock
+	}
+
+	for _, interaction := range cassette.Interactions {
+		mock.WhenCalledWith(interaction.Args...).
+			WillPrintToStdOut(interaction.Stdout).
+			WillPrintToStdErr(interaction.Stderr).
+			WillExitWith(interaction.ExitCode)
+	}
+	return mock
+}
+
+func (recorder *recorder) record(invocationId int, invocation Invocation) {
+	env := map[string]string{}
+	for _, key := range recorder.envKeys {
+		if value, found := invocation.env[key]; found {
+			env[key] = value
+		}
+	}
+	recorder.pending[invocationId] = Interaction{Args: invocation.args, Env: env, Stdin: invocation.stdin}
+}
+
+func (recorder *recorder) complete(result invocationResult) error {
+	interaction, found := recorder.pending[result.InvocationId]
+	if !found {
+		return fmt.Errorf("unknown invocation %d", result.InvocationId)
+	}
+	delete(recorder.pending, result.InvocationId)
+
+	interaction.Stdout = result.Stdout
+	interaction.Stderr = result.Stderr
+	interaction.ExitCode = result.ExitCode
+	position := sort.SearchInts(recorder.completed, result.InvocationId)
+	recorder.completed = append(recorder.completed, 0)
+	copy(recorder.completed[position+1:], recorder.completed[position:])
+	recorder.completed[position] = result.InvocationId
+	recorder.cassette.Interactions = append(recorder.cassette.Interactions, Interaction{})
+	copy(recorder.cassette.Interactions[position+1:], recorder.cassette.Interactions[position:])
+	recorder.cassette.Interactions[position] = interaction
+	return recorder.cassette.Save(recorder.cassettePath)
+}
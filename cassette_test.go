@@ -0,0 +1,131 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pivotal-cf/go-binmock"
+)
+
+var _ = Describe("Cassettes", func() {
+	var binMock *binmock.Mock
+	var currentMockFailure *mockFailure
+	var workDir, realExecutable, cassettePath string
+
+	BeforeEach(func() {
+		currentMockFailure = &mockFailure{}
+		binMock = binmock.NewBinMock(currentMockFailure.Fail)
+
+		var err error
+		workDir, err = ioutil.TempDir("", "binmock-cassette")
+		Expect(err).NotTo(HaveOccurred())
+
+		realExecutable = writeScript(workDir, "real", `echo "out $@"; echo "err $TARGET" >&2; cat; exit 3`)
+		cassettePath = filepath.Join(workDir, "cassette.json")
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(workDir)
+	})
+
+	Describe("when recording", func() {
+		BeforeEach(func() {
+			binMock.Record(realExecutable, cassettePath, "TARGET")
+
+			command := MakeCommand(binMock.Path, "deploy", "-n")
+			command.Env = []string{"TARGET=prod", "OTHER=ignored"}
+			command.Stdin = bytes.NewBufferString("yes\n")
+			session := StartCommand(command)
+
+			Expect(session).To(gexec.Exit(3))
+			Expect(session.Out).To(gbytes.Say("out deploy -n"))
+			Expect(session.Out).To(gbytes.Say("yes"))
+			Expect(session.Err).To(gbytes.Say("err prod"))
+		})
+
+		It("saves the interactions with the real executable to the cassette", func() {
+			cassette, err := binmock.LoadCassette(cassettePath)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(cassette.Executable).To(Equal(realExecutable))
+			Expect(cassette.Interactions).To(Equal([]binmock.Interaction{{
+				Args:     []string{"deploy", "-n"},
+				Env:      map[string]string{"TARGET": "prod"},
+				Stdin:    []string{"yes"},
+				Stdout:   "out deploy -n\nyes\n",
+				Stderr:   "err prod\n",
+				ExitCode: 3,
+			}}))
+		})
+
+		It("captures the invocations", func() {
+			Expect(binMock.Invocations()).To(HaveLen(1))
+			Expect(binMock.Invocations()[0].Args()).To(Equal([]string{"deploy", "-n"}))
+		})
+
+		It("can be replayed", func() {
+			replayMock := binmock.NewBinMock(currentMockFailure.Fail)
+			replayMock.Replay(cassettePath)
+
+			session := RunCommand(replayMock.Path, "deploy", "-n")
+
+			Expect(session).To(gexec.Exit(3))
+			Expect(session.Out).To(gbytes.Say("out deploy -n"))
+			Expect(session.Err).To(gbytes.Say("err prod"))
+			Expect(currentMockFailure.called).To(BeFalse())
+		})
+	})
+
+	It("saves overlapping interactions in the order they were invoked", func() {
+		slowExecutable := writeScript(workDir, "slow", `test "$1" = slow && sleep 1; echo "$1"`)
+		binMock.Record(slowExecutable, cassettePath)
+
+		slow, err := gexec.Start(MakeCommand(binMock.Path, "slow"), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(binMock.InvocationCount, 10*time.Second).Should(Equal(1))
+		RunCommand(binMock.Path, "fast")
+		Eventually(slow, 10*time.Second).Should(gexec.Exit(0))
+
+		cassette, err := binmock.LoadCassette(cassettePath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cassette.Interactions).To(HaveLen(2))
+		Expect(cassette.Interactions[0].Args).To(Equal([]string{"slow"}))
+		Expect(cassette.Interactions[1].Args).To(Equal([]string{"fast"}))
+	})
+
+	It("fails to replay a cassette that doesn't exist", func() {
+		binMock.Replay(filepath.Join(workDir, "missing.json"))
+
+		Expect(currentMockFailure.lastMessage).To(ContainSubstring("cant load cassette"))
+	})
+})
+
+func writeScript(dir, name, script string) string {
+	path := filepath.Join(dir, name)
+	Expect(ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755)).To(Succeed())
+	return path
+}
@@ -19,7 +19,11 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os/exec"
+	"syscall"
 
 	"bufio"
 	"bytes"
@@ -32,43 +36,194 @@ var serverUrl string
 func main() {
 	jsonInvocationRequest := InvocationRequest{}
 	jsonInvocationRequest.Id = identifier
+	jsonInvocationRequest.Pid = os.Getpid()
+	jsonInvocationRequest.Argv0 = os.Args[0]
+	jsonInvocationRequest.Dir, _ = os.Getwd()
 	jsonInvocationRequest.Args = os.Args[1:]
 	jsonInvocationRequest.Env = os.Environ()
+	jsonInvocationRequest.Streams = []StreamInfo{
+		inspectStream(os.Stdin),
+		inspectStream(os.Stdout),
+		inspectStream(os.Stderr),
+	}
 
-	scanner := bufio.NewScanner(os.Stdin)
+	stdin, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		panic(err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(stdin))
 	for scanner.Scan() {
 		jsonInvocationRequest.Stdin = append(jsonInvocationRequest.Stdin, scanner.Text())
 	}
 
+	jsonInvocationResponse := InvocationResponse{}
+	post("/", jsonInvocationRequest, &jsonInvocationResponse)
+
+	if jsonInvocationResponse.Passthrough != "" {
+		result := passthrough(jsonInvocationResponse.Passthrough, stdin)
+		result.Id = identifier
+		result.InvocationId = jsonInvocationResponse.InvocationId
+		post("/result", result, &struct{}{})
+		exit(result.ExitCode)
+	}
+
+	errors := applyEffects(jsonInvocationResponse.Effects)
+	fmt.Fprint(os.Stdout, jsonInvocationResponse.Stdout)
+	fmt.Fprint(os.Stderr, jsonInvocationResponse.Stderr)
+	exit(jsonInvocationResponse.ExitCode, errors...)
+}
+
+func exit(exitCode int, errors ...string) {
+	post("/exit", ProcessExit{Id: identifier, Pid: os.Getpid(), Errors: errors}, &struct{}{})
+	os.Exit(exitCode)
+}
+
+func applyEffects(effects []FileEffect) []string {
+	var errors []string
+	for _, effect := range effects {
+		var err error
+		switch effect.Action {
+		case "write":
+			err = ioutil.WriteFile(effect.Path, []byte(effect.Contents), 0644)
+		case "remove":
+			err = os.Remove(effect.Path)
+		case "mkdir":
+			err = os.MkdirAll(effect.Path, 0755)
+		case "chmod":
+			err = os.Chmod(effect.Path, effect.Mode)
+		default:
+			err = fmt.Errorf("unknown side effect %s on %s", effect.Action, effect.Path)
+		}
+		if err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	return errors
+}
+
+func post(path string, request interface{}, response interface{}) {
 	buffer := bytes.NewBufferString("")
-	if err := json.NewEncoder(buffer).Encode(jsonInvocationRequest); err != nil {
+	if err := json.NewEncoder(buffer).Encode(request); err != nil {
 		panic(err)
 	}
 
-	response, err := http.Post("http://"+serverUrl, "", buffer)
+	httpResponse, err := http.Post("http://"+serverUrl+path, "", buffer)
 	if err != nil {
 		panic(err)
 	}
+	defer httpResponse.Body.Close()
 
-	jsonInvocationResponse := InvocationResponse{}
-	if err := json.NewDecoder(response.Body).Decode(&jsonInvocationResponse); err != nil {
+	if err := json.NewDecoder(httpResponse.Body).Decode(response); err != nil {
 		panic(err)
 	}
+}
 
-	fmt.Fprint(os.Stdout, jsonInvocationResponse.Stdout)
-	fmt.Fprint(os.Stderr, jsonInvocationResponse.Stderr)
-	os.Exit(jsonInvocationResponse.ExitCode)
+func passthrough(executable string, stdin []byte) InvocationResult {
+	stdout := bytes.NewBufferString("")
+	stderr := bytes.NewBufferString("")
+
+	command := exec.Command(executable, os.Args[1:]...)
+	command.Stdin = bytes.NewReader(stdin)
+	command.Stdout = io.MultiWriter(os.Stdout, stdout)
+	command.Stderr = io.MultiWriter(os.Stderr, stderr)
+
+	exitCode := 0
+	err := command.Start()
+	if err == nil {
+		post("/child", ProcessChild{Id: identifier, Pid: os.Getpid(), Child: command.Process.Pid}, &struct{}{})
+		err = command.Wait()
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			exitCode = exitErr.Sys().(syscall.WaitStatus).ExitStatus()
+		} else {
+			fmt.Fprintln(io.MultiWriter(os.Stderr, stderr), err)
+			exitCode = 127
+		}
+	}
+	return InvocationResult{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: exitCode}
+}
+
+func inspectStream(file *os.File) StreamInfo {
+	info, err := file.Stat()
+	if err != nil {
+		return StreamInfo{Kind: "closed"}
+	}
+
+	if devNull, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, devNull) {
+		return StreamInfo{Kind: "devnull", Target: os.DevNull}
+	}
+
+	mode := info.Mode()
+	switch {
+	case mode&os.ModeNamedPipe != 0:
+		return StreamInfo{Kind: "pipe"}
+	case mode&os.ModeSocket != 0:
+		return StreamInfo{Kind: "socket"}
+	case mode&os.ModeCharDevice != 0:
+		return StreamInfo{Kind: "tty", Target: streamTarget(file)}
+	case mode.IsRegular():
+		return StreamInfo{Kind: "file", Target: streamTarget(file)}
+	}
+	return StreamInfo{Kind: "unknown"}
+}
+
+func streamTarget(file *os.File) string {
+	target, err := os.Readlink(fmt.Sprintf("/proc/self/fd/%d", file.Fd()))
+	if err != nil {
+		return ""
+	}
+	return target
 }
 
 type InvocationRequest struct {
-	Id    string
-	Args  []string
-	Env   []string
-	Stdin []string
+	Id      string
+	Pid     int
+	Argv0   string
+	Dir     string
+	Args    []string
+	Env     []string
+	Stdin   []string
+	Streams []StreamInfo
+}
+
+type StreamInfo struct {
+	Kind   string
+	Target string
 }
 
 type InvocationResponse struct {
-	Stdout   string
-	Stderr   string
-	ExitCode int
+	Stdout       string
+	Stderr       string
+	ExitCode     int
+	Passthrough  string
+	InvocationId int
+	Effects      []FileEffect
+}
+
+type FileEffect struct {
+	Action   string
+	Path     string
+	Contents string
+	Mode     os.FileMode
+}
+
+type InvocationResult struct {
+	Id           string
+	InvocationId int
+	Stdout       string
+	Stderr       string
+	ExitCode     int
+}
+
+type ProcessChild struct {
+	Id    string
+	Pid   int
+	Child int
+}
+
+type ProcessExit struct {
+	Id     string
+	Pid    int
+	Errors []string
 }
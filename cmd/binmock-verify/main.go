@@ -0,0 +1,56 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// binmock-verify checks that a real executable still behaves as recorded in a cassette.
+//
+// Usage: binmock-verify <cassette.json> [executable]
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pivotal-cf/go-binmock"
+)
+
+func main() {
+	if len(os.Args) < 2 || len(os.Args) > 3 {
+		fmt.Fprintln(os.Stderr, "Usage: binmock-verify <cassette.json> [executable]")
+		os.Exit(2)
+	}
+
+	cassette, err := binmock.LoadCassette(os.Args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cant load cassette %s %v\n", os.Args[1], err)
+		os.Exit(2)
+	}
+
+	executable := ""
+	if len(os.Args) == 3 {
+		executable = os.Args[2]
+	}
+
+	report, err := binmock.VerifyCassette(cassette, executable)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	fmt.Println(report)
+	if !report.Passed() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// ContractReport lists the differences between recorded or stubbed interactions and the behaviour of the real executable
+type ContractReport struct {
+	Executable  string
+	Differences []ContractDifference
+	// Skipped describes the stubs that couldn't be verified, such as the ones constrained with InvokedAs
+	Skipped []string
+}
+
+// ContractDifference is a mismatch between the expected and actual outcome of running the real executable with Args
+type ContractDifference struct {
+	Args     []string
+	Field    string
+	Expected string
+	Actual   string
+}
+
+// Passed is true when the real executable behaved as recorded or stubbed
+func (report ContractReport) Passed() bool {
+	return len(report.Differences) == 0
+}
+
+func (report ContractReport) String() string {
+	if report.Passed() && len(report.Skipped) == 0 {
+		return fmt.Sprintf("%s honours the contract", report.Executable)
+	}
+
+	output := bytes.NewBufferString(fmt.Sprintf("%s doesn't honour the contract:\n", report.Executable))
+	if report.Passed() {
+		output = bytes.NewBufferString(fmt.Sprintf("%s honours the contract, except for the skipped stubs:\n", report.Executable))
+	}
+	for _, difference := range report.Differences {
+		fmt.Fprintf(output, "  %v: %s\n    expected: %q\n    actual:   %q\n", difference.Args, difference.Field, difference.Expected, difference.Actual)
+	}
+	for _, skipped := range report.Skipped {
+		fmt.Fprintf(output, "  skipped %s\n", skipped)
+	}
+	return output.String()
+}
+
+// VerifyCassette runs each of the interactions of the cassette against the executable, in a scratch directory,
+// and reports any differences in standard out, standard error and exit code.
+// If executable is empty, the one the cassette was recorded against is used
+func VerifyCassette(cassette Cassette, executable string) (ContractReport, error) {
+	if executable == "" {
+		executable = cassette.Executable
+	}
+	return verifyInteractions(executable, cassette.Interactions)
+}
+
+// Verify runs each of the responses of the stubs of the mock that expect specific arguments against the executable,
+// in a scratch directory, and reports any differences in standard out, standard error and exit code.
+// Stubs constrained with InvokedAs are skipped, as the executable can't be run under another name, and listed in the report
+func (mock *Mock) Verify(executable string) (ContractReport, error) {
+	mock.lock.Lock()
+	interactions := []Interaction{}
+	var skipped []string
+	for _, stub := range mock.mappings {
+		if stub.expectedArgs == nil {
+			continue
+		}
+		if stub.invokedAs != "" {
+			skipped = append(skipped, stub.describe())
+			continue
+		}
+		for _, response := range stub.responses() {
+			interactions = append(interactions, Interaction{
+				Args:     stub.expectedArgs,
+				Stdout:   response.stdout,
+				Stderr:   response.stderr,
+				ExitCode: response.exitCode,
+			})
+		}
+	}
+	mock.lock.Unlock()
+
+	report, err := verifyInteractions(executable, interactions)
+	report.Skipped = skipped
+	return report, err
+}
+
+func verifyInteractions(executable string, interactions []Interaction) (ContractReport, error) {
+	report := ContractReport{Executable: executable}
+	for _, interaction := range interactions {
+		actual, err := runInScratchDir(executable, interaction)
+		if err != nil {
+			return report, err
+		}
+
+		if actual.Stdout != interaction.Stdout {
+			report.Differences = append(report.Differences, ContractDifference{interaction.Args, "stdout", interaction.Stdout, actual.Stdout})
+		}
+		if actual.Stderr != interaction.Stderr {
+			report.Differences = append(report.Differences, ContractDifference{interaction.Args, "stderr", interaction.Stderr, actual.Stderr})
+		}
+		if actual.ExitCode != interaction.ExitCode {
+			report.Differences = append(report.Differences, ContractDifference{interaction.Args, "exit code", fmt.Sprint(interaction.ExitCode), fmt.Sprint(actual.ExitCode)})
+		}
+	}
+	return report, nil
+}
+
+func runInScratchDir(executable string, interaction Interaction) (Interaction, error) {
+	scratchDir, err := ioutil.TempDir("", "binmock_contract")
+	if err != nil {
+		return Interaction{}, err
+	}
+	defer os.RemoveAll(scratchDir)
+
+	executable, err = resolveExecutable(executable)
+	if err != nil {
+		return Interaction{Args: interaction.Args}, fmt.Errorf("cant find %s %v", executable, err)
+	}
+
+	stdout := bytes.NewBufferString("")
+	stderr := bytes.NewBufferString("")
+
+	command := exec.Command(executable, interaction.Args...)
+	command.Dir = scratchDir
+	command.Env = os.Environ()
+	for key, value := range interaction.Env {
+		command.Env = append(command.Env, key+"="+value)
+	}
+	if len(interaction.Stdin) > 0 {
+		command.Stdin = strings.NewReader(strings.Join(interaction.Stdin, "\n") + "\n")
+	}
+	command.Stdout = stdout
+	command.Stderr = stderr
+
+	actual := Interaction{Args: interaction.Args}
+	if err := command.Run(); err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return actual, fmt.Errorf("cant run %s %v", executable, err)
+		}
+		actual.ExitCode = exitErr.Sys().(syscall.WaitStatus).ExitStatus()
+	}
+	actual.Stdout = stdout.String()
+	actual.Stderr = stderr.String()
+	return actual, nil
+}
+
+// resolveExecutable makes the path of the executable absolute, so that it can be run from the scratch directory
+func resolveExecutable(executable string) (string, error) {
+	if !strings.ContainsRune(executable, filepath.Separator) {
+		return exec.LookPath(executable)
+	}
+	return filepath.Abs(executable)
+}
@@ -0,0 +1,140 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"io/ioutil"
+	"os"
+
+	"github.com/pivotal-cf/go-binmock"
+)
+
+var _ = Describe("Contract verification", func() {
+	var workDir, realExecutable string
+
+	BeforeEach(func() {
+		var err error
+		workDir, err = ioutil.TempDir("", "binmock-contract")
+		Expect(err).NotTo(HaveOccurred())
+
+		realExecutable = writeScript(workDir, "real", `echo "version 2"; test -z "$(ls)" || exit 4`)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(workDir)
+	})
+
+	Describe("of a cassette", func() {
+		It("passes when the real executable behaves as recorded", func() {
+			cassette := binmock.Cassette{Executable: realExecutable, Interactions: []binmock.Interaction{
+				{Args: []string{"--version"}, Stdout: "version 2\n"},
+			}}
+
+			report, err := binmock.VerifyCassette(cassette, "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Passed()).To(BeTrue())
+		})
+
+		It("runs executables given by a relative path", func() {
+			originalDir, err := os.Getwd()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(os.Chdir(workDir)).To(Succeed())
+			defer os.Chdir(originalDir)
+			cassette := binmock.Cassette{Executable: "./real", Interactions: []binmock.Interaction{
+				{Args: []string{"--version"}, Stdout: "version 2\n"},
+			}}
+
+			report, err := binmock.VerifyCassette(cassette, "")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Passed()).To(BeTrue())
+		})
+
+		It("reports the differences when the real executable has changed", func() {
+			cassette := binmock.Cassette{Executable: "/old/path", Interactions: []binmock.Interaction{
+				{Args: []string{"--version"}, Stdout: "version 1\n", Stderr: "deprecated\n", ExitCode: 1},
+			}}
+
+			report, err := binmock.VerifyCassette(cassette, realExecutable)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Passed()).To(BeFalse())
+			Expect(report.Differences).To(ConsistOf(
+				binmock.ContractDifference{Args: []string{"--version"}, Field: "stdout", Expected: "version 1\n", Actual: "version 2\n"},
+				binmock.ContractDifference{Args: []string{"--version"}, Field: "stderr", Expected: "deprecated\n", Actual: ""},
+				binmock.ContractDifference{Args: []string{"--version"}, Field: "exit code", Expected: "1", Actual: "0"},
+			))
+			Expect(report.String()).To(ContainSubstring(`[--version]: stdout`))
+		})
+
+		It("fails when the executable can't be run", func() {
+			cassette := binmock.Cassette{Interactions: []binmock.Interaction{{Args: []string{"--version"}}}}
+
+			_, err := binmock.VerifyCassette(cassette, "/does/not/exist")
+
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("of the stubs of a mock", func() {
+		It("runs the stubs with expected arguments against the real executable", func() {
+			mock := binmock.NewBinMock(Fail)
+			mock.WhenCalledWith("--version").WillPrintToStdOut("version 2\n")
+			mock.WhenCalled().WillPrintToStdOut("ignored")
+			mock.WhenCalledWith("--help").WillExitWith(3)
+
+			report, err := mock.Verify(realExecutable)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Differences).To(ConsistOf(
+				binmock.ContractDifference{Args: []string{"--help"}, Field: "stdout", Expected: "", Actual: "version 2\n"},
+				binmock.ContractDifference{Args: []string{"--help"}, Field: "exit code", Expected: "3", Actual: "0"},
+			))
+		})
+
+		It("runs each of the responses of a sequence", func() {
+			mock := binmock.NewBinMock(Fail)
+			mock.WhenCalledWith("--version").WillRespondInSequence(
+				binmock.Response{Stdout: "version 2\n"},
+				binmock.Response{Stdout: "version 3\n"},
+			)
+
+			report, err := mock.Verify(realExecutable)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Differences).To(ConsistOf(
+				binmock.ContractDifference{Args: []string{"--version"}, Field: "stdout", Expected: "version 3\n", Actual: "version 2\n"},
+			))
+		})
+
+		It("reports the stubs constrained to a name as skipped", func() {
+			mock := binmock.NewBinMock(Fail)
+			mock.WhenCalledWith("--version").InvokedAs("gunzip").WillPrintToStdOut("gunzip 1.6\n")
+
+			report, err := mock.Verify(realExecutable)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Passed()).To(BeTrue())
+			Expect(report.Skipped).To(ConsistOf("invoked as gunzip and called with [--version], exits with 0"))
+			Expect(report.String()).To(ContainSubstring("skipped invoked as gunzip and called with [--version]"))
+		})
+	})
+})
@@ -0,0 +1,79 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"bytes"
+	"fmt"
+)
+
+func (mock *Mock) mismatchReport(summary string, args []string, env map[string]string, stub *InvocationStub) string {
+	report := bytes.NewBufferString(summary)
+	if mock.name != "" {
+		fmt.Fprintf(report, "\n\nMock: %s (%s)\nInvocation: #%d\n", mock.name, mock.Path, mock.calls)
+	} else {
+		fmt.Fprintf(report, "\n\nMock: %s\nInvocation: #%d\n", mock.Path, mock.calls)
+	}
+
+	if stub != nil {
+		redactions := mock.activeRedactions()
+		fmt.Fprintf(report, "Arguments:\n%s", argumentsDiff(args, stub.expectedArgs, func(arg string) string {
+			return redactions.redact(arg, env)
+		}))
+	}
+
+	fmt.Fprintf(report, "Remaining stubs:\n")
+	remaining := 0
+	for i := mock.currentMappingIndex; i < len(mock.mappings); i++ {
+		if mock.matchingMode == MatchByPrecedence && !mock.mappings[i].selectableByPrecedence() {
+			continue
+		}
+		fmt.Fprintf(report, "    #%d %s\n", i+1, mock.mappings[i].describe())
+		remaining++
+	}
+	if remaining == 0 {
+		fmt.Fprintf(report, "    none\n")
+	}
+
+	fmt.Fprintf(report, "Previous invocations:\n")
+	if len(mock.invocations) == 0 {
+		fmt.Fprintf(report, "    none\n")
+	}
+	for i, invocation := range mock.invocations {
+		fmt.Fprint(report, invocation.redact(fmt.Sprintf("    #%d %v\n", i+1, invocation.args)))
+	}
+	return report.String()
+}
+
+// argumentsDiff compares the arguments one by one, redacting each of them before quoting it,
+// as quoting would escape the secrets so that they are no longer found in the message
+func argumentsDiff(actual, expected []string, redact func(string) string) string {
+	diff := bytes.NewBufferString("")
+	for i := 0; i < len(actual) || i < len(expected); i++ {
+		switch {
+		case i >= len(expected):
+			fmt.Fprintf(diff, "  > [%d] got %q, expected nothing\n", i, redact(actual[i]))
+		case i >= len(actual):
+			fmt.Fprintf(diff, "  > [%d] got nothing, expected %q\n", i, redact(expected[i]))
+		case actual[i] != expected[i]:
+			fmt.Fprintf(diff, "  > [%d] got %q, expected %q\n", i, redact(actual[i]), redact(expected[i]))
+		default:
+			fmt.Fprintf(diff, "    [%d] %q\n", i, redact(actual[i]))
+		}
+	}
+	return diff.String()
+}
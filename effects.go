@@ -0,0 +1,78 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"fmt"
+	"os"
+)
+
+type fileEffect struct {
+	Action   string
+	Path     string
+	Contents string
+	Mode     os.FileMode
+
+	atArg   int
+	fromArg bool
+}
+
+// WillWriteFile sets up the mock to write the contents to the file on invocation, before exiting.
+// Relative paths are resolved in the working directory of the mock process
+func (stub *InvocationStub) WillWriteFile(path, contents string) *InvocationStub {
+	return stub.addEffect(fileEffect{Action: "write", Path: path, Contents: contents})
+}
+
+// WillWriteFileAtArg sets up the mock to write the contents to the file passed as the argument at the index, e.g. 1 for `-f out.sql`
+func (stub *InvocationStub) WillWriteFileAtArg(index int, contents string) *InvocationStub {
+	return stub.addEffect(fileEffect{Action: "write", Contents: contents, atArg: index, fromArg: true})
+}
+
+// WillRemoveFile sets up the mock to remove the file or empty directory on invocation
+func (stub *InvocationStub) WillRemoveFile(path string) *InvocationStub {
+	return stub.addEffect(fileEffect{Action: "remove", Path: path})
+}
+
+// WillCreateDir sets up the mock to create the directory, along with any missing parents, on invocation
+func (stub *InvocationStub) WillCreateDir(path string) *InvocationStub {
+	return stub.addEffect(fileEffect{Action: "mkdir", Path: path})
+}
+
+// WillChmod sets up the mock to change the mode of the file on invocation
+func (stub *InvocationStub) WillChmod(path string, mode os.FileMode) *InvocationStub {
+	return stub.addEffect(fileEffect{Action: "chmod", Path: path, Mode: mode})
+}
+
+func (stub *InvocationStub) addEffect(effect fileEffect) *InvocationStub {
+	defer stub.lock()()
+	stub.effects = append(stub.effects, effect)
+	return stub
+}
+
+func (stub *InvocationStub) fileEffects(args []string) ([]fileEffect, error) {
+	var effects []fileEffect
+	for _, effect := range stub.effects {
+		if effect.fromArg {
+			if effect.atArg < 0 || effect.atArg >= len(args) {
+				return nil, fmt.Errorf("no argument at index %d to write the file to", effect.atArg)
+			}
+			effect.Path = args[effect.atArg]
+		}
+		effects = append(effects, effect)
+	}
+	return effects, nil
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pivotal-cf/go-binmock"
+)
+
+var _ = Describe("Side effects", func() {
+	var binMock *binmock.Mock
+	var currentMockFailure *mockFailure
+	var workDir string
+
+	BeforeEach(func() {
+		currentMockFailure = &mockFailure{}
+		binMock = binmock.NewBinMock(currentMockFailure.Fail)
+
+		var err error
+		workDir, err = ioutil.TempDir("", "binmock-effects")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(workDir)
+	})
+
+	runIn := func(dir string, args ...string) {
+		command := MakeCommand(binMock.Path, args...)
+		command.Dir = dir
+		StartCommand(command)
+	}
+
+	It("writes files relative to the working directory of the mock process", func() {
+		binMock.WhenCalled().WillWriteFile("out.sql", "CREATE TABLE")
+
+		runIn(workDir)
+
+		Expect(ioutil.ReadFile(filepath.Join(workDir, "out.sql"))).To(Equal([]byte("CREATE TABLE")))
+		Expect(currentMockFailure.called).To(BeFalse())
+	})
+
+	It("writes files named by an argument", func() {
+		binMock.WhenCalled().WillWriteFileAtArg(1, "archive")
+
+		runIn(workDir, "-cf", "x.tar")
+
+		Expect(ioutil.ReadFile(filepath.Join(workDir, "x.tar"))).To(Equal([]byte("archive")))
+	})
+
+	It("fails when there is no argument at the index", func() {
+		binMock.WhenCalled().WillWriteFileAtArg(3, "archive")
+
+		runIn(workDir, "-cf", "x.tar")
+
+		Expect(currentMockFailure.lastMessage).To(ContainSubstring("Cant set up the side effects of the call to the mock with [-cf x.tar]: no argument at index 3"))
+	})
+
+	It("creates directories, changes modes and removes files", func() {
+		Expect(ioutil.WriteFile(filepath.Join(workDir, "stale"), []byte{}, 0644)).To(Succeed())
+		binMock.WhenCalled().
+			WillCreateDir("backups/today").
+			WillWriteFile("backups/today/run.sh", "#!/bin/sh").
+			WillChmod("backups/today/run.sh", 0700).
+			WillRemoveFile("stale")
+
+		runIn(workDir)
+
+		info, err := os.Stat(filepath.Join(workDir, "backups", "today", "run.sh"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0700)))
+		Expect(filepath.Join(workDir, "stale")).NotTo(BeAnExistingFile())
+	})
+
+	It("applies the side effects of each response in a sequence", func() {
+		binMock.WhenCalled().WillWriteFile("first", "").ThenReturn().WillWriteFile("second", "")
+
+		runIn(workDir)
+
+		Expect(filepath.Join(workDir, "first")).To(BeAnExistingFile())
+		Expect(filepath.Join(workDir, "second")).NotTo(BeAnExistingFile())
+	})
+
+	It("fails when a side effect cant be applied", func() {
+		binMock.WhenCalledWith("purge").WillRemoveFile("missing")
+
+		runIn(workDir, "purge")
+
+		Expect(currentMockFailure.lastMessage).To(ContainSubstring("Failed to apply the side effects of the call to the mock with [purge]:"))
+		Expect(currentMockFailure.lastMessage).To(ContainSubstring("missing"))
+	})
+
+	It("redacts the environment in the failure to apply a side effect", func() {
+		binMock.RedactEnv("PGPASSWORD")
+		binMock.WhenCalled().WillRemoveFile("missing")
+
+		command := MakeCommand(binMock.Path, "--password=hunter2")
+		command.Dir = workDir
+		command.Env = []string{"PGPASSWORD=hunter2"}
+		StartCommand(command)
+
+		Expect(currentMockFailure.lastMessage).To(ContainSubstring("with [--password=[REDACTED]]"))
+		Expect(currentMockFailure.lastMessage).NotTo(ContainSubstring("hunter2"))
+	})
+})
@@ -0,0 +1,55 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import "sync"
+
+// Gate holds the invocations answered by a stub until the test releases them, see InvocationStub.BlockUntilReleased
+type Gate struct {
+	called       chan struct{}
+	released     chan struct{}
+	calledOnce   sync.Once
+	releasedOnce sync.Once
+}
+
+func newGate() *Gate {
+	return &Gate{called: make(chan struct{}), released: make(chan struct{})}
+}
+
+// Wait blocks until the stub of the gate is invoked
+func (gate *Gate) Wait() {
+	<-gate.called
+}
+
+// Release lets the held invocations finish, and all further invocations pass without blocking
+func (gate *Gate) Release() {
+	gate.releasedOnce.Do(func() { close(gate.released) })
+}
+
+// pass blocks until the gate is released, or the process of the invocation is killed
+func (gate *Gate) pass(killed <-chan struct{}) {
+	gate.calledOnce.Do(func() { close(gate.called) })
+	select {
+	case <-gate.released:
+	case <-killed:
+	}
+}
+
+type heldInvocation struct {
+	invocation Invocation
+	killed     chan struct{}
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultMaxInlineLength = 80
+
+// StubGenerator turns recorded interactions into the Go code that sets up the equivalent stubs.
+// Outputs longer than MaxInlineLength are written to files in TestdataDir, and read with MustReadFile in the generated code
+type StubGenerator struct {
+	MockName        string
+	TestdataDir     string
+	MaxInlineLength int
+}
+
+// InteractionsFrom converts invocations of a mock, e.g. of a spy, into interactions that can be used to generate stubs
+func InteractionsFrom(invocations []Invocation) []Interaction {
+	interactions := []Interaction{}
+	for _, invocation := range invocations {
+		interactions = append(interactions, Interaction{
+			Args:     invocation.args,
+			Stdin:    invocation.stdin,
+			Stdout:   invocation.stdout,
+			Stderr:   invocation.stderr,
+			ExitCode: invocation.exitCode,
+		})
+	}
+	return interactions
+}
+
+// MustReadFile returns the contents of a file, and panics if it can't be read. It is used by generated stubs
+func MustReadFile(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// Generate returns the Go code setting up a stub for each of the interactions, in order
+func (generator StubGenerator) Generate(interactions []Interaction) (string, error) {
+	code := bytes.NewBufferString("")
+	for i, interaction := range interactions {
+		quotedArgs := []string{}
+		for _, arg := range interaction.Args {
+			quotedArgs = append(quotedArgs, fmt.Sprintf("%q", arg))
+		}
+		chain := []string{fmt.Sprintf("%s.WhenCalledWith(%s)", generator.mockName(), strings.Join(quotedArgs, ", "))}
+
+		if interaction.Stdout != "" {
+			stdout, err := generator.literal(interaction.Stdout, fmt.Sprintf("%s_%d_stdout.txt", generator.mockName(), i+1))
+			if err != nil {
+				return "", err
+			}
+			chain = append(chain, fmt.Sprintf("WillPrintToStdOut(%s)", stdout))
+		}
+		if interaction.Stderr != "" {
+			stderr, err := generator.literal(interaction.Stderr, fmt.Sprintf("%s_%d_stderr.txt", generator.mockName(), i+1))
+			if err != nil {
+				return "", err
+			}
+			chain = append(chain, fmt.Sprintf("WillPrintToStdErr(%s)", stderr))
+		}
+		if interaction.ExitCode != 0 {
+			chain = append(chain, fmt.Sprintf("WillExitWith(%d)", interaction.ExitCode))
+		}
+
+		fmt.Fprintln(code, strings.Join(chain, ".\n\t"))
+	}
+	return code.String(), nil
+}
+
+func (generator StubGenerator) mockName() string {
+	if generator.MockName == "" {
+		return "mock"
+	}
+	return generator.MockName
+}
+
+func (generator StubGenerator) literal(value, fileName string) (string, error) {
+	maxInlineLength := generator.MaxInlineLength
+	if maxInlineLength == 0 {
+		maxInlineLength = defaultMaxInlineLength
+	}
+	if len(value) <= maxInlineLength {
+		return fmt.Sprintf("%q", value), nil
+	}
+
+	testdataDir := generator.TestdataDir
+	if testdataDir == "" {
+		testdataDir = "testdata"
+	}
+	if err := os.MkdirAll(testdataDir, 0755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(testdataDir, fileName)
+	if err := ioutil.WriteFile(path, []byte(value), 0644); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("binmock.MustReadFile(%q)", path), nil
+}
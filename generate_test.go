@@ -0,0 +1,85 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pivotal-cf/go-binmock"
+)
+
+var _ = Describe("Stub generation", func() {
+	var testdataDir string
+
+	BeforeEach(func() {
+		var err error
+		testdataDir, err = ioutil.TempDir("", "binmock-testdata")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(testdataDir)
+	})
+
+	It("generates a stub for each interaction", func() {
+		generator := binmock.StubGenerator{MockName: "mockBosh", TestdataDir: testdataDir}
+
+		code, err := generator.Generate([]binmock.Interaction{
+			{Args: []string{"deploy", "-n"}, Stdout: "done\n", ExitCode: 0},
+			{Args: []string{"delete"}, Stderr: "no \"deployment\"", ExitCode: 1},
+			{Args: []string{}},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal(`mockBosh.WhenCalledWith("deploy", "-n").
+	WillPrintToStdOut("done\n")
+mockBosh.WhenCalledWith("delete").
+	WillPrintToStdErr("no \"deployment\"").
+	WillExitWith(1)
+mockBosh.WhenCalledWith()
+`))
+	})
+
+	It("extracts long outputs into testdata files", func() {
+		longOutput := strings.Repeat("line\n", 10)
+		generator := binmock.StubGenerator{MockName: "mockTar", TestdataDir: testdataDir, MaxInlineLength: 20}
+
+		code, err := generator.Generate([]binmock.Interaction{{Args: []string{"-tf", "x.tar"}, Stdout: longOutput}})
+
+		Expect(err).NotTo(HaveOccurred())
+		stdoutPath := filepath.Join(testdataDir, "mockTar_1_stdout.txt")
+		Expect(code).To(ContainSubstring(`WillPrintToStdOut(binmock.MustReadFile("` + stdoutPath + `"))`))
+		Expect(binmock.MustReadFile(stdoutPath)).To(Equal(longOutput))
+	})
+
+	It("generates stubs from the invocations of a mock", func() {
+		mock := binmock.NewBinMock(Fail)
+		mock.WhenCalledWith("status").WillPrintToStdOut("clean").WillExitWith(2)
+		RunCommand(mock.Path, "status")
+
+		code, err := binmock.StubGenerator{MockName: "mockGit"}.Generate(binmock.InteractionsFrom(mock.Invocations()))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(code).To(Equal("mockGit.WhenCalledWith(\"status\").\n\tWillPrintToStdOut(\"clean\").\n\tWillExitWith(2)\n"))
+	})
+})
@@ -21,7 +21,13 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
 
+	"fmt"
+	"io/ioutil"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"time"
 
 	"bytes"
 
@@ -135,6 +141,41 @@ var _ = Describe("go-binmock", func() {
 			Expect(binMock.Invocations()[0].Env()).To(HaveKeyWithValue("foo", "bar"))
 		})
 
+		It("captures environment values containing '='", func() {
+			binMock.WhenCalled()
+
+			command := MakeCommand(binMock.Path)
+			command.Env = []string{"TOKEN=YWJj==", "DSN=host=db user=admin", "EMPTY="}
+			StartCommand(command)
+
+			Expect(binMock.Invocations()[0].Env()).To(HaveKeyWithValue("TOKEN", "YWJj=="))
+			Expect(binMock.Invocations()[0].Env()).To(HaveKeyWithValue("DSN", "host=db user=admin"))
+			Expect(binMock.Invocations()[0].Env()).To(HaveKeyWithValue("EMPTY", ""))
+		})
+
+		It("captures the environment as an ordered list", func() {
+			binMock.WhenCalled()
+
+			command := MakeCommand(binMock.Path)
+			command.Env = []string{"B=2", "A=1"}
+			StartCommand(command)
+
+			Expect(binMock.Invocations()[0].EnvList()).To(Equal([]string{"B=2", "A=1"}))
+		})
+
+		It("reports the environment changes relative to a base environment", func() {
+			binMock.WhenCalled()
+
+			command := MakeCommand(binMock.Path)
+			command.Env = []string{"KEPT=same", "CHANGED=new", "ADDED=yes"}
+			StartCommand(command)
+
+			changes := binMock.Invocations()[0].EnvDiff([]string{"KEPT=same", "CHANGED=old", "REMOVED=gone"})
+			Expect(changes.Added).To(Equal(map[string]string{"ADDED": "yes"}))
+			Expect(changes.Changed).To(Equal(map[string]string{"CHANGED": "new"}))
+			Expect(changes.Removed).To(Equal([]string{"REMOVED"}))
+		})
+
 		It("captures stdin", func() {
 			binMock.WhenCalled()
 
@@ -144,6 +185,33 @@ var _ = Describe("go-binmock", func() {
 
 			Expect(binMock.Invocations()[0].Stdin()).To(ConsistOf("stdin", "nextStdin"))
 		})
+
+		It("captures what the standard streams are connected to", func() {
+			binMock.WhenCalled()
+
+			RunCommand(binMock.Path)
+
+			Expect(binMock.Invocations()[0].StdinKind()).To(Equal(binmock.StreamDevNull))
+			Expect(binMock.Invocations()[0].StdinTarget()).To(Equal(os.DevNull))
+			Expect(binMock.Invocations()[0].StdoutKind()).To(Equal(binmock.StreamPipe))
+			Expect(binMock.Invocations()[0].StderrKind()).To(Equal(binmock.StreamPipe))
+		})
+
+		It("captures the path of a file the output is redirected to", func() {
+			binMock.WhenCalled()
+
+			logFile, err := ioutil.TempFile("", "binmock-log")
+			Expect(err).NotTo(HaveOccurred())
+			defer os.Remove(logFile.Name())
+			defer logFile.Close()
+
+			command := MakeCommand(binMock.Path)
+			command.Stdout = logFile
+			Expect(command.Run()).To(Succeed())
+
+			Expect(binMock.Invocations()[0].StdoutKind()).To(Equal(binmock.StreamFile))
+			Expect(binMock.Invocations()[0].StdoutTarget()).To(Equal(logFile.Name()))
+		})
 	})
 
 	Describe("when multiple stubs are defined", func() {
@@ -181,6 +249,51 @@ var _ = Describe("go-binmock", func() {
 				Expect(currentMockFailure.called).To(BeTrue())
 				Expect(currentMockFailure.lastMessage).To(ContainSubstring("Expected [two] to equal [one]"))
 			})
+
+			It("reports the context of the mismatch", func() {
+				binMock.WhenCalledWith("one").WillExitWith(0)
+				binMock.WhenCalledWith("two", "2").WillExitWith(2)
+				binMock.WhenCalled().WillExitWith(3)
+
+				RunCommand(binMock.Path, "one")
+				RunCommand(binMock.Path, "two", "3", "extra")
+
+				message := currentMockFailure.lastMessage
+				Expect(message).To(ContainSubstring("Mock: " + binMock.Path))
+				Expect(message).To(ContainSubstring("Invocation: #2"))
+				Expect(message).To(ContainSubstring(`    [0] "two"`))
+				Expect(message).To(ContainSubstring(`  > [1] got "3", expected "2"`))
+				Expect(message).To(ContainSubstring(`  > [2] got "extra", expected nothing`))
+				Expect(message).To(ContainSubstring("Remaining stubs:\n    #3 called with any arguments, exits with 3"))
+				Expect(message).To(ContainSubstring("Previous invocations:\n    #1 [one]"))
+			})
+
+			It("redacts secrets that quoting would escape in the argument diff", func() {
+				binMock.RedactValues(`pa"ss\word`)
+				binMock.WhenCalledWith("--password", "admin")
+
+				RunCommand(binMock.Path, "--password", `pa"ss\word`)
+
+				Expect(currentMockFailure.lastMessage).To(ContainSubstring(`  > [1] got "[REDACTED]", expected "admin"`))
+				Expect(currentMockFailure.lastMessage).NotTo(ContainSubstring(`ss\\word`))
+				Expect(currentMockFailure.lastMessage).NotTo(ContainSubstring(`pa"ss`))
+			})
+
+			It("redacts each previous invocation with its own environment", func() {
+				binMock.RedactEnv("PGPASSWORD")
+				binMock.WhenCalled()
+				binMock.WhenCalledWith("two")
+
+				command := MakeCommand(binMock.Path, "--password=hunter2")
+				command.Env = []string{"PGPASSWORD=hunter2"}
+				StartCommand(command)
+				command = MakeCommand(binMock.Path, "three")
+				command.Env = []string{"PGPASSWORD=swordfish"}
+				StartCommand(command)
+
+				Expect(currentMockFailure.lastMessage).To(ContainSubstring("Previous invocations:\n    #1 [--password=[REDACTED]]"))
+				Expect(currentMockFailure.lastMessage).NotTo(ContainSubstring("hunter2"))
+			})
 		})
 
 		Describe("and more invocations occur", func() {
@@ -198,6 +311,401 @@ var _ = Describe("go-binmock", func() {
 		})
 	})
 
+	Describe("when secrets are forbidden in the arguments", func() {
+		BeforeEach(func() {
+			binMock.WhenCalled()
+		})
+
+		It("fails when a secret is passed as an argument", func() {
+			binMock.ForbidInArgs("p@ssw0rd")
+
+			RunCommand(binMock.Path, "--user", "admin", "--password=p@ssw0rd")
+
+			Expect(currentMockFailure.called).To(BeTrue())
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Secret passed as an argument to the mock"))
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("--password=[REDACTED]"))
+			Expect(currentMockFailure.lastMessage).NotTo(ContainSubstring("p@ssw0rd"))
+		})
+
+		It("doesn't fail when the secret is not passed as an argument", func() {
+			binMock.ForbidInArgs("p@ssw0rd")
+
+			command := MakeCommand(binMock.Path, "--user", "admin")
+			command.Env = []string{"PGPASSWORD=p@ssw0rd"}
+			StartCommand(command)
+
+			Expect(currentMockFailure.called).To(BeFalse())
+			Expect(binMock.Invocations()).To(HaveLen(1))
+		})
+
+		It("applies secrets forbidden for all mocks", func() {
+			binmock.ForbidInArgs("s3cr3t")
+			defer binmock.ResetPolicy()
+
+			RunCommand(binMock.Path, "s3cr3t")
+
+			Expect(currentMockFailure.called).To(BeTrue())
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Call with [[REDACTED]]"))
+		})
+
+		It("fails when a required environment variable is not set", func() {
+			binMock.RequireInEnv("PGPASSWORD")
+
+			command := MakeCommand(binMock.Path)
+			command.Env = []string{}
+			StartCommand(command)
+
+			Expect(currentMockFailure.called).To(BeTrue())
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Expected PGPASSWORD to be set in the environment of the mock"))
+		})
+	})
+
+	Describe("when secrets are redacted", func() {
+		It("redacts literal values from failure messages", func() {
+			binMock.RedactValues("p@ssw0rd")
+
+			RunCommand(binMock.Path, "--password", "p@ssw0rd")
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Too many calls to the mock! Last call with [--password [REDACTED]]"))
+		})
+
+		It("redacts the values of environment variables from failure messages", func() {
+			binMock.RedactEnv("PGPASSWORD")
+			binMock.WhenCalledWith("dbname")
+
+			command := MakeCommand(binMock.Path, "--password=hunter2")
+			command.Env = []string{"PGPASSWORD=hunter2"}
+			StartCommand(command)
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Expected [--password=[REDACTED]] to equal [dbname]"))
+		})
+
+		It("redacts anything matching a pattern from failure messages", func() {
+			binMock.RedactPatterns(regexp.MustCompile(`token-[0-9]+`))
+
+			RunCommand(binMock.Path, "--auth", "token-1234")
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("[--auth [REDACTED]]"))
+		})
+
+		It("redacts values registered for all mocks", func() {
+			binmock.RedactValues("s3cr3t")
+			defer binmock.ResetRedactions()
+
+			RunCommand(binMock.Path, "s3cr3t")
+
+			Expect(currentMockFailure.lastMessage).NotTo(ContainSubstring("s3cr3t"))
+		})
+
+		It("redacts the invocation dump but not the accessors", func() {
+			binMock.RedactEnv("PGPASSWORD")
+			binMock.WhenCalled()
+
+			command := MakeCommand(binMock.Path, "hunter2")
+			command.Env = []string{"PGPASSWORD=hunter2"}
+			StartCommand(command)
+
+			invocation := binMock.Invocations()[0]
+			Expect(invocation.String()).To(Equal("args: [[REDACTED]], env: [PGPASSWORD=[REDACTED]], stdin: []"))
+			Expect(invocation.Args()).To(Equal([]string{"hunter2"}))
+			Expect(invocation.Env()).To(HaveKeyWithValue("PGPASSWORD", "hunter2"))
+		})
+	})
+
+	Describe("when a named mock is created", func() {
+		var namedMock *binmock.Mock
+
+		BeforeEach(func() {
+			namedMock = binmock.NewNamedBinMock("pg_dump", currentMockFailure.Fail)
+		})
+
+		It("builds an executable with that name", func() {
+			Expect(filepath.Base(namedMock.Path)).To(Equal("pg_dump"))
+		})
+
+		It("can be found on the PATH by name", func() {
+			namedMock.WhenCalled().WillPrintToStdOut("dumped")
+
+			command := exec.Command("sh", "-c", "pg_dump")
+			command.Env = []string{"PATH=" + filepath.Dir(namedMock.Path) + string(os.PathListSeparator) + os.Getenv("PATH")}
+
+			Expect(StartCommand(command).Out).To(gbytes.Say("dumped"))
+		})
+
+		It("uses the name in failure messages", func() {
+			RunCommand(namedMock.Path, "dbname")
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Too many calls to the mock pg_dump! Last call with [dbname]"))
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Mock: pg_dump (" + namedMock.Path + ")"))
+		})
+
+		It("fails when the name is a path", func() {
+			binmock.NewNamedBinMock("bin/pg_dump", currentMockFailure.Fail)
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring(`invalid mock name "bin/pg_dump"`))
+		})
+	})
+
+	Describe("when linked under several names", func() {
+		var linkDir string
+
+		BeforeEach(func() {
+			var err error
+			linkDir, err = ioutil.TempDir("", "binmock-links")
+			Expect(err).NotTo(HaveOccurred())
+
+			binMock.LinkAs(linkDir, "gzip", "gunzip")
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(linkDir)
+		})
+
+		It("records the name it was invoked as", func() {
+			binMock.WhenCalled()
+
+			RunCommand(filepath.Join(linkDir, "gunzip"), "backup.gz")
+
+			Expect(binMock.Invocations()[0].InvokedAs()).To(Equal("gunzip"))
+			Expect(binMock.Invocations()[0].Argv0()).To(Equal(filepath.Join(linkDir, "gunzip")))
+		})
+
+		It("matches stubs constrained by the invoked name", func() {
+			binMock.WhenCalled().InvokedAs("gzip").WillPrintToStdOut("compressed")
+			binMock.WhenCalled().InvokedAs("gunzip").WillPrintToStdOut("decompressed")
+
+			Expect(RunCommand(filepath.Join(linkDir, "gzip")).Out).To(gbytes.Say("compressed"))
+			Expect(RunCommand(filepath.Join(linkDir, "gunzip")).Out).To(gbytes.Say("decompressed"))
+			Expect(currentMockFailure.called).To(BeFalse())
+		})
+
+		It("fails when invoked under a different name than expected", func() {
+			binMock.WhenCalled().InvokedAs("gzip")
+
+			RunCommand(filepath.Join(linkDir, "gunzip"))
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Expected the mock to be invoked as gzip but it was invoked as gunzip"))
+		})
+	})
+
+	Describe("when installed at a path", func() {
+		var root string
+
+		BeforeEach(func() {
+			var err error
+			root, err = ioutil.TempDir("", "binmock-root")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(root)
+		})
+
+		It("creates the parent directories and routes the invocations to the mock", func() {
+			installPath := filepath.Join(root, "var", "vcap", "packages", "mysql", "bin", "mysql")
+			binMock.InstallAt(installPath)
+			binMock.WhenCalledWith("--version").WillPrintToStdOut("mysql 5.7")
+
+			Expect(RunCommand(installPath, "--version").Out).To(gbytes.Say("mysql 5.7"))
+			Expect(binMock.Invocations()).To(HaveLen(1))
+		})
+
+		It("removes the installed copies on cleanup", func() {
+			installPath := filepath.Join(root, "bin", "mysql")
+			binMock.InstallAt(installPath)
+			binMock.LinkAs(root, "mysqldump")
+
+			binMock.Cleanup()
+
+			Expect(installPath).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(root, "mysqldump")).NotTo(BeAnExistingFile())
+			Expect(binMock.Path).To(BeAnExistingFile())
+		})
+	})
+
+	Describe("when a stub responds in sequence", func() {
+		It("returns the responses on successive matching calls", func() {
+			binMock.WhenCalledWith("connect").WillExitWith(1).WillPrintToStdErr("timeout").
+				ThenReturn().WillExitWith(1).
+				ThenReturn().WillPrintToStdOut("connected")
+
+			Expect(RunCommand(binMock.Path, "connect")).To(gexec.Exit(1))
+			Expect(RunCommand(binMock.Path, "connect")).To(gexec.Exit(1))
+			session := RunCommand(binMock.Path, "connect")
+			Expect(session).To(gexec.Exit(0))
+			Expect(session.Out).To(gbytes.Say("connected"))
+			Expect(currentMockFailure.called).To(BeFalse())
+		})
+
+		It("moves on to the next stub once used up", func() {
+			binMock.WhenCalledWith("connect").WillRespondInSequence(
+				binmock.Response{ExitCode: 1},
+				binmock.Response{Stdout: "connected"},
+			)
+			binMock.WhenCalledWith("disconnect")
+
+			RunCommand(binMock.Path, "connect")
+			RunCommand(binMock.Path, "connect")
+			RunCommand(binMock.Path, "disconnect")
+			Expect(currentMockFailure.called).To(BeFalse())
+
+			RunCommand(binMock.Path, "connect")
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Too many calls to the mock"))
+		})
+
+		It("can repeat the last response once exhausted", func() {
+			binMock.WhenCalledWith("connect").WillRespondInSequence(
+				binmock.Response{ExitCode: 1},
+				binmock.Response{ExitCode: 0, Stdout: "connected"},
+			).WhenExhausted(binmock.RepeatLastWhenExhausted)
+			binMock.WhenCalledWith("disconnect").WillExitWith(3)
+
+			Expect(RunCommand(binMock.Path, "connect")).To(gexec.Exit(1))
+			Expect(RunCommand(binMock.Path, "connect")).To(gexec.Exit(0))
+			Expect(RunCommand(binMock.Path, "connect").Out).To(gbytes.Say("connected"))
+			Expect(RunCommand(binMock.Path, "disconnect")).To(gexec.Exit(3))
+			Expect(currentMockFailure.called).To(BeFalse())
+		})
+	})
+
+	Describe("when matching stubs by precedence", func() {
+		BeforeEach(func() {
+			binMock.MatchStubs(binmock.MatchByPrecedence)
+			binMock.WhenCalled().WillPrintToStdOut("shared setup")
+		})
+
+		It("uses the most recently declared matching stub", func() {
+			binMock.WhenCalledWith("status").WillPrintToStdOut("override")
+
+			Expect(RunCommand(binMock.Path, "status").Out).To(gbytes.Say("override"))
+			Expect(RunCommand(binMock.Path, "status").Out).To(gbytes.Say("override"))
+			Expect(RunCommand(binMock.Path, "summary").Out).To(gbytes.Say("shared setup"))
+			Expect(currentMockFailure.called).To(BeFalse())
+		})
+
+		It("uses the stub with the highest priority first", func() {
+			binMock.WhenCalledWith("status").WillPrintToStdOut("important").Priority(10)
+			binMock.WhenCalledWith("status").WillPrintToStdOut("later")
+
+			Expect(RunCommand(binMock.Path, "status").Out).To(gbytes.Say("important"))
+		})
+
+		It("fails when no stub matches", func() {
+			binMock.Reset()
+			binMock.WhenCalledWith("status")
+
+			RunCommand(binMock.Path, "summary")
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("No stub of the mock matches the call with [summary]"))
+		})
+
+		It("reports only the stubs that can still answer", func() {
+			binMock.Reset()
+			binMock.WhenCalledWith("status").WillRespondInSequence(binmock.Response{}, binmock.Response{})
+			binMock.WhenCalledWith("deploy")
+
+			RunCommand(binMock.Path, "status")
+			RunCommand(binMock.Path, "status")
+			RunCommand(binMock.Path, "status")
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Remaining stubs:\n    #2 called with [deploy], exits with 0\nPrevious invocations:"))
+		})
+	})
+
+	Describe("when a fallback stub is defined", func() {
+		BeforeEach(func() {
+			binMock.Otherwise().WillPrintToStdOut("version 1.0")
+			binMock.WhenCalledWith("deploy").WillPrintToStdOut("deployed")
+		})
+
+		It("answers the calls that match no other stub", func() {
+			Expect(RunCommand(binMock.Path, "--version").Out).To(gbytes.Say("version 1.0"))
+			Expect(RunCommand(binMock.Path, "deploy").Out).To(gbytes.Say("deployed"))
+			Expect(RunCommand(binMock.Path, "--version").Out).To(gbytes.Say("version 1.0"))
+
+			Expect(currentMockFailure.called).To(BeFalse())
+			Expect(binMock.Invocations()).To(HaveLen(3))
+		})
+	})
+
+	Describe("when an unexpected call policy is set", func() {
+		It("fails with the configured exit code", func() {
+			binMock.OnUnexpectedCall(binmock.FailAndExitWith(127))
+
+			session := RunCommand(binMock.Path, "unexpected")
+
+			Expect(session).To(gexec.Exit(127))
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Too many calls to the mock"))
+		})
+
+		It("responds silently", func() {
+			binMock.OnUnexpectedCall(binmock.RespondSilently)
+			binMock.WhenCalledWith("expected")
+
+			session := RunCommand(binMock.Path, "unexpected")
+
+			Expect(session).To(gexec.Exit(0))
+			Expect(currentMockFailure.called).To(BeFalse())
+			Expect(binMock.Invocations()[0].Args()).To(Equal([]string{"unexpected"}))
+		})
+	})
+
+	Describe("when learning", func() {
+		BeforeEach(func() {
+			binMock.Learn().WillPrintToStdOut("default").WillExitWith(0)
+			binMock.WhenCalledWith("known").WillPrintToStdOut("stubbed")
+		})
+
+		It("answers the calls that don't match a stub with the default response", func() {
+			Expect(RunCommand(binMock.Path, "unknown").Out).To(gbytes.Say("default"))
+			Expect(RunCommand(binMock.Path, "known").Out).To(gbytes.Say("stubbed"))
+			Expect(RunCommand(binMock.Path, "another", "one")).To(gexec.Exit(0))
+
+			Expect(currentMockFailure.called).To(BeFalse())
+			Expect(binMock.Invocations()).To(HaveLen(3))
+		})
+
+		It("summarises the unmatched invocations with suggested stubs", func() {
+			RunCommand(binMock.Path, "unknown")
+			RunCommand(binMock.Path, "known")
+			RunCommand(binMock.Path, "another", "one")
+
+			Expect(binMock.UnmatchedInvocations()).To(HaveLen(2))
+
+			summary := gbytes.NewBuffer()
+			binMock.PrintLearningSummary(summary)
+			Expect(summary).To(gbytes.Say(`2 unmatched invocations of the mock:`))
+			Expect(summary).To(gbytes.Say(`\[unknown\]`))
+			Expect(summary).To(gbytes.Say(`\[another one\]`))
+			Expect(summary).To(gbytes.Say(`Suggested stubs:\nmock.WhenCalledWith\("unknown"\)\nmock.WhenCalledWith\("another", "one"\)`))
+		})
+
+		It("redacts secrets that quoting would escape in the suggested stubs", func() {
+			binMock.RedactValues(`pa"ss\word`)
+
+			RunCommand(binMock.Path, "--password", `pa"ss\word`)
+
+			summary := binMock.LearningSummary()
+			Expect(summary).To(ContainSubstring(`mock.WhenCalledWith("--password", "[REDACTED]")`))
+			Expect(summary).NotTo(ContainSubstring(`pa\"ss`))
+		})
+
+		It("redacts the environment of each unmatched invocation in the summary", func() {
+			binMock.RedactEnv("PGPASSWORD")
+			for _, password := range []string{"hunter2", "swordfish"} {
+				command := MakeCommand(binMock.Path, "--password="+password)
+				command.Env = []string{"PGPASSWORD=" + password}
+				StartCommand(command)
+			}
+
+			summary := binMock.LearningSummary()
+
+			Expect(summary).To(ContainSubstring("[--password=[REDACTED]]"))
+			Expect(summary).NotTo(ContainSubstring("hunter2"))
+			Expect(summary).NotTo(ContainSubstring("swordfish"))
+		})
+	})
+
 	Describe("when multiple mock binaries are created", func() {
 		It("returns the response from the correct mock", func() {
 			firstMock := binmock.NewBinMock(currentMockFailure.Fail)
@@ -215,6 +723,200 @@ var _ = Describe("go-binmock", func() {
 		})
 	})
 
+	Describe("when invocations are constrained to happen in order across mocks", func() {
+		var mysqldump, gzip *binmock.Mock
+		var dump, compress *binmock.InvocationStub
+
+		BeforeEach(func() {
+			mysqldump = binmock.NewNamedBinMock("mysqldump", currentMockFailure.Fail)
+			gzip = binmock.NewNamedBinMock("gzip", currentMockFailure.Fail)
+
+			dump = mysqldump.WhenCalled()
+			compress = gzip.WhenCalled()
+			binmock.InOrder(dump, compress)
+		})
+
+		It("numbers the invocations of all the mocks in sequence", func() {
+			RunCommand(mysqldump.Path)
+			RunCommand(gzip.Path)
+
+			Expect(gzip.Invocations()[0].Sequence()).To(BeNumerically(">", mysqldump.Invocations()[0].Sequence()))
+		})
+
+		It("passes when the order is respected", func() {
+			RunCommand(mysqldump.Path)
+			RunCommand(gzip.Path)
+
+			Expect(currentMockFailure.called).To(BeFalse())
+		})
+
+		It("fails when the order is violated", func() {
+			session := RunCommand(gzip.Path, "backup.sql")
+
+			Expect(session).To(gexec.Exit(1))
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Expected the mock mysqldump called with any arguments, exits with 0 to be invoked before the mock gzip called with any arguments, exits with 0! Call with [backup.sql]"))
+		})
+	})
+
+	Describe("when invoked in the background", func() {
+		BeforeEach(func() {
+			binMock.WhenCalled()
+			binMock.WhenCalled()
+		})
+
+		runInBackground := func(args ...string) {
+			go func() {
+				defer GinkgoRecover()
+				time.Sleep(100 * time.Millisecond)
+				RunCommand(binMock.Path, args...)
+			}()
+		}
+
+		It("waits for the invocations", func() {
+			runInBackground("one")
+			runInBackground("two")
+
+			invocations := binMock.WaitForInvocations(2, 10*time.Second)
+
+			Expect(invocations).To(HaveLen(2))
+			Expect(currentMockFailure.called).To(BeFalse())
+		})
+
+		It("fails when the invocations don't happen in time", func() {
+			binMock.WaitForInvocations(1, 50*time.Millisecond)
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Expected the mock to be invoked 1 times within 50ms, but it was invoked 0 times"))
+		})
+
+		It("delivers the invocations on a channel", func() {
+			RunCommand(binMock.Path, "one")
+			invocations, stop := binMock.InvocationsChan()
+			defer stop()
+			runInBackground("two")
+
+			Eventually(invocations, 10*time.Second).Should(Receive(WithTransform(binmock.Invocation.Args, Equal([]string{"one"}))))
+			Eventually(invocations, 10*time.Second).Should(Receive(WithTransform(binmock.Invocation.Args, Equal([]string{"two"}))))
+		})
+
+		It("closes the channel once stopped", func() {
+			invocations, stop := binMock.InvocationsChan()
+
+			stop()
+			stop()
+
+			Eventually(invocations).Should(BeClosed())
+			RunCommand(binMock.Path, "one")
+			Expect(binMock.Invocations()).To(HaveLen(1))
+		})
+
+		It("can be used with Eventually and Consistently", func() {
+			runInBackground("one")
+
+			Eventually(binMock.InvocationCount, 10*time.Second).Should(Equal(1))
+			Consistently(binMock.InvocationCount, 200*time.Millisecond).Should(Equal(1))
+		})
+	})
+
+	Describe("when the stub blocks until released", func() {
+		var gate *binmock.Gate
+
+		BeforeEach(func() {
+			gate = binMock.Otherwise().WillPrintToStdOut("done").BlockUntilReleased()
+		})
+
+		start := func(args ...string) *gexec.Session {
+			session, err := gexec.Start(MakeCommand(binMock.Path, args...), GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			return session
+		}
+
+		It("holds the invocations until released", func() {
+			first := start("one")
+			second := start("two")
+
+			gate.Wait()
+			Eventually(binMock.RunningInvocations, 10*time.Second).Should(HaveLen(2))
+			Consistently(first, 200*time.Millisecond).ShouldNot(gexec.Exit())
+
+			gate.Release()
+
+			Eventually(first, 10*time.Second).Should(gexec.Exit(0))
+			Eventually(second, 10*time.Second).Should(gexec.Exit(0))
+			Expect(first.Out).To(gbytes.Say("done"))
+			Expect(binMock.RunningInvocations()).To(BeEmpty())
+			Expect(binMock.Invocations()).To(HaveLen(2))
+		})
+
+		It("doesn't hold the invocations once released", func() {
+			gate.Release()
+
+			session := RunCommand(binMock.Path)
+
+			Expect(session.ExitCode()).To(Equal(0))
+			Expect(binMock.RunningInvocations()).To(BeEmpty())
+		})
+	})
+
+	Describe("when processes are left running", func() {
+		var gate *binmock.Gate
+		var session *gexec.Session
+
+		BeforeEach(func() {
+			gate = binMock.Otherwise().BlockUntilReleased()
+
+			var err error
+			session, err = gexec.Start(MakeCommand(binMock.Path, "watch"), GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			gate.Wait()
+		})
+
+		AfterEach(func() {
+			gate.Release()
+		})
+
+		It("reports them", func() {
+			binMock.AssertNoRunningProcesses()
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Expected the mock to have no running processes, but found:"))
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring(fmt.Sprintf("pid %d called with [watch]", session.Command.Process.Pid)))
+		})
+
+		It("redacts the environment of each process in the report", func() {
+			binMock.RedactEnv("PGPASSWORD")
+			command := MakeCommand(binMock.Path, "--password=hunter2")
+			command.Env = []string{"PGPASSWORD=hunter2"}
+			_, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(binMock.RunningInvocations, 10*time.Second).Should(HaveLen(2))
+
+			binMock.AssertNoRunningProcesses()
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("called with [--password=[REDACTED]]"))
+			Expect(currentMockFailure.lastMessage).NotTo(ContainSubstring("hunter2"))
+			binMock.KillAll()
+		})
+
+		It("kills them", func() {
+			binMock.KillAll()
+
+			Eventually(session, 10*time.Second).Should(gexec.Exit())
+			binMock.AssertNoRunningProcesses()
+			Expect(currentMockFailure.called).To(BeFalse())
+			Expect(binMock.RunningInvocations()).To(BeEmpty())
+		})
+	})
+
+	Describe("when processes exit", func() {
+		It("doesn't report them", func() {
+			binMock.WhenCalled()
+
+			RunCommand(binMock.Path)
+
+			binMock.AssertNoRunningProcesses()
+			Expect(currentMockFailure.called).To(BeFalse())
+		})
+	})
+
 	Describe("when reset", func() {
 		It("ignores earlier expectations", func() {
 			binMock.WhenCalled().WillExitWith(0)
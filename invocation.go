@@ -16,21 +16,83 @@
 
 package binmock
 
-import "strings"
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
 
 // Invocation represents an invocation of the mock
 type Invocation struct {
-	args  []string
-	env   map[string]string
-	stdin []string
+	sequence uint64
+	argv0    string
+	pid      int
+	args     []string
+	envList  []string
+	env      map[string]string
+	stdin    []string
+	streams  []Stream
+	dir      string
+	files    map[string]string
+	trees    map[string][]FileEntry
+
+	exitCode int
+	stdout   string
+	stderr   string
+
+	redactions *redactions
 }
 
-func newInvocation(args, env, stdin []string) Invocation {
+func newInvocation(args, env, stdin []string, streams []Stream) Invocation {
 	return Invocation{
-		args:  args,
-		env:   parseEnv(env),
-		stdin: stdin,
+		args:    args,
+		envList: env,
+		env:     parseEnv(env),
+		stdin:   stdin,
+		streams: streams,
+	}
+}
+
+// Sequence is the position of the invocation among the invocations of all the mocks, starting at 1
+func (invocation Invocation) Sequence() uint64 {
+	return invocation.sequence
+}
+
+// Argv0 represents the name the mock was invoked with, as received in argv[0]
+func (invocation Invocation) Argv0() string {
+	return invocation.argv0
+}
+
+// Dir represents the working directory of the mock process
+func (invocation Invocation) Dir() string {
+	return invocation.dir
+}
+
+// CapturedFile returns the contents of the file at the time of invocation, as captured with Mock.CaptureFilesAtArgs or Mock.CaptureFiles.
+// The path is the argument as passed or, for patterns, the match relative to the working directory
+func (invocation Invocation) CapturedFile(path string) string {
+	return invocation.files[path]
+}
+
+// CapturedFiles returns the contents of all the captured files, by path
+func (invocation Invocation) CapturedFiles() map[string]string {
+	files := map[string]string{}
+	for path, contents := range invocation.files {
+		files[path] = contents
 	}
+	return files
+}
+
+// DirTree returns the entries of the directory at the time of invocation, as captured with Mock.CaptureDirTree.
+// The dir is as passed to CaptureDirTree, or "." for the working directory
+func (invocation Invocation) DirTree(dir string) []FileEntry {
+	return invocation.trees[dir]
+}
+
+// InvokedAs represents the base name the mock was invoked with, e.g. "gunzip" when invoked through a link called gunzip
+func (invocation Invocation) InvokedAs() string {
+	return filepath.Base(invocation.argv0)
 }
 
 // Args represents the arguments passed to the mock when it was invoked
@@ -38,23 +100,135 @@ func (invocation Invocation) Args() []string {
 	return invocation.args
 }
 
-// Env represents the environment at the time of invocation
+// Env represents the environment at the time of invocation.
+// If a variable is defined more than once, the first definition wins, as it does for os.Getenv
 func (invocation Invocation) Env() map[string]string {
 	return invocation.env
 }
 
+// EnvList represents the environment at the time of invocation as it was received, in "key=value" form,
+// preserving order and duplicates
+func (invocation Invocation) EnvList() []string {
+	return invocation.envList
+}
+
+// EnvChanges describes how the environment of an invocation differs from a base environment
+type EnvChanges struct {
+	Added   map[string]string
+	Changed map[string]string
+	Removed []string
+}
+
+// EnvDiff returns the variables added, changed or removed in the environment of the invocation
+// relative to base, which is a list in "key=value" form such as the one returned by os.Environ()
+func (invocation Invocation) EnvDiff(base []string) EnvChanges {
+	baseEnv := parseEnv(base)
+	changes := EnvChanges{Added: map[string]string{}, Changed: map[string]string{}, Removed: []string{}}
+
+	for key, value := range invocation.env {
+		baseValue, found := baseEnv[key]
+		if !found {
+			changes.Added[key] = value
+		} else if baseValue != value {
+			changes.Changed[key] = value
+		}
+	}
+	for key := range baseEnv {
+		if _, found := invocation.env[key]; !found {
+			changes.Removed = append(changes.Removed, key)
+		}
+	}
+	sort.Strings(changes.Removed)
+	return changes
+}
+
 // Stdin represents the standard input steam received by the mock as a slice of lines
 func (invocation Invocation) Stdin() []string {
 	return invocation.stdin
 }
 
+// ExitCode represents the exit code of the invocation, either stubbed or, for spies and recordings, the one of the real executable
+func (invocation Invocation) ExitCode() int {
+	return invocation.exitCode
+}
+
+// Stdout represents what the invocation printed to standard out
+func (invocation Invocation) Stdout() string {
+	return invocation.stdout
+}
+
+// Stderr represents what the invocation printed to standard error
+func (invocation Invocation) Stderr() string {
+	return invocation.stderr
+}
+
+// StdinKind represents what the standard input of the mock was connected to
+func (invocation Invocation) StdinKind() StreamKind {
+	return invocation.stream(0).Kind
+}
+
+// StdinTarget represents the path the standard input of the mock was connected to, if known
+func (invocation Invocation) StdinTarget() string {
+	return invocation.stream(0).Target
+}
+
+// StdoutKind represents what the standard output of the mock was connected to
+func (invocation Invocation) StdoutKind() StreamKind {
+	return invocation.stream(1).Kind
+}
+
+// StdoutTarget represents the path the standard output of the mock was connected to, if known
+func (invocation Invocation) StdoutTarget() string {
+	return invocation.stream(1).Target
+}
+
+// StderrKind represents what the standard error of the mock was connected to
+func (invocation Invocation) StderrKind() StreamKind {
+	return invocation.stream(2).Kind
+}
+
+// StderrTarget represents the path the standard error of the mock was connected to, if known
+func (invocation Invocation) StderrTarget() string {
+	return invocation.stream(2).Target
+}
+
+func (invocation Invocation) stream(fd int) Stream {
+	if fd >= len(invocation.streams) {
+		return Stream{Kind: StreamUnknown}
+	}
+	return invocation.streams[fd]
+}
+
+// String dumps the invocation with all the registered secrets redacted.
+// The other accessors of the invocation always return the raw values
+func (invocation Invocation) String() string {
+	return invocation.redact(fmt.Sprintf("args: %v, env: %v, stdin: %v", invocation.args, invocation.envList, invocation.stdin))
+}
+
+// redact hides the secrets registered at the time of the invocation, including the values of its environment variables
+func (invocation Invocation) redact(text string) string {
+	if invocation.redactions == nil {
+		return text
+	}
+	return invocation.redactions.redact(text, invocation.env)
+}
+
 func parseEnv(envVars []string) map[string]string {
 	parsedVars := map[string]string{}
 
 	for _, v := range envVars {
-		parts := strings.Split(v, "=")
-
-		parsedVars[parts[0]] = parts[1]
+		key, value := splitEnvVar(v)
+		if _, found := parsedVars[key]; !found {
+			parsedVars[key] = value
+		}
 	}
 	return parsedVars
 }
+
+func splitEnvVar(envVar string) (string, string) {
+	parts := strings.SplitN(envVar, "=", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
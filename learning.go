@@ -0,0 +1,87 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Learn puts the mock in learning mode: invocations that don't match the next stub are answered with the returned
+// default response instead of failing, and recorded as unmatched so they can be reported with PrintLearningSummary
+func (mock *Mock) Learn() *InvocationStub {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	mock.learning = &InvocationStub{whenExhausted: RepeatLastWhenExhausted, mock: mock}
+	return mock.learning
+}
+
+// UnmatchedInvocations returns the invocations that got the default response in learning mode
+func (mock *Mock) UnmatchedInvocations() []Invocation {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	unmatched := []Invocation{}
+	for _, index := range mock.unmatched {
+		unmatched = append(unmatched, mock.invocations[index])
+	}
+	return unmatched
+}
+
+// LearningSummary describes the unmatched invocations of the mock in learning mode, with suggested stubs for them
+func (mock *Mock) LearningSummary() string {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	summary := bytes.NewBufferString("")
+	if len(mock.unmatched) == 0 {
+		fmt.Fprintf(summary, "No unmatched invocations of %s\n", mock.displayName())
+		return summary.String()
+	}
+
+	fmt.Fprintf(summary, "%d unmatched invocations of %s:\n", len(mock.unmatched), mock.displayName())
+	redactions := mock.activeRedactions()
+	interactions := []Interaction{}
+	for _, index := range mock.unmatched {
+		invocation := mock.invocations[index]
+		args := []string{}
+		for _, arg := range invocation.args {
+			args = append(args, redactions.redact(invocation.redact(arg), nil))
+		}
+		fmt.Fprintf(summary, "    %v\n", args)
+		interactions = append(interactions, Interaction{Args: args})
+	}
+
+	// the arguments are redacted before generating the stubs, as quoting them would escape the secrets
+	suggestions, _ := StubGenerator{}.Generate(interactions)
+	fmt.Fprintf(summary, "Suggested stubs:\n%s", suggestions)
+	return summary.String()
+}
+
+// PrintLearningSummary writes the LearningSummary of the mock, e.g. to ginkgo.GinkgoWriter at the end of a test
+func (mock *Mock) PrintLearningSummary(writer io.Writer) {
+	fmt.Fprint(writer, mock.LearningSummary())
+}
+
+func (mock *Mock) learn(request invocationRequest) invocationResponse {
+	if response, matched := mock.respondWithNextStub(request); matched {
+		return response
+	}
+
+	response := mock.respond(request, mock.learning)
+	mock.unmatched = append(mock.unmatched, len(mock.invocations)-1)
+	return response
+}
@@ -16,29 +16,202 @@
 
 package binmock
 
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+)
+
 // InvocationStub offers a fluid API to set up the behaviour on invocation of the binary mock
 type InvocationStub struct {
 	expectedArgs []string
+	invokedAs    string
 
 	exitCode int
 	stdout   string
 	stderr   string
+	effects  []fileEffect
+
+	priority int
+	gate     *Gate
+
+	mock       *Mock
+	orderings  []*ordering
+	answeredAt []uint64
+
+	head          *InvocationStub
+	sequence      []*InvocationStub
+	served        int
+	whenExhausted ExhaustedBehaviour
+}
+
+// Response is one of the responses of a stub that responds in sequence
+type Response struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// ExhaustedBehaviour is what a stub that responds in sequence does once all its responses have been used
+type ExhaustedBehaviour int
+
+const (
+	// FailWhenExhausted uses up the stub, so further invocations are matched against the next stubs
+	FailWhenExhausted ExhaustedBehaviour = iota
+	// RepeatLastWhenExhausted keeps answering with the last response, until an invocation matches the next stub
+	RepeatLastWhenExhausted
+)
+
+// InvokedAs constrains the stub to invocations of the mock through a link with the given name, see Mock.LinkAs
+func (stub *InvocationStub) InvokedAs(name string) *InvocationStub {
+	defer stub.lock()()
+	stub.first().invokedAs = name
+	return stub
+}
+
+// Priority sets the precedence of the stub when the mock matches stubs with MatchByPrecedence.
+// Stubs with a higher priority are matched first. The default priority is 0
+func (stub *InvocationStub) Priority(priority int) *InvocationStub {
+	defer stub.lock()()
+	stub.first().priority = priority
+	return stub
+}
+
+// ThenReturn adds another response to the stub, used on the next matching invocation
+func (stub *InvocationStub) ThenReturn() *InvocationStub {
+	defer stub.lock()()
+	return stub.thenReturn()
+}
+
+// WillRespondInSequence sets up the responses of the stub to successive matching invocations
+func (stub *InvocationStub) WillRespondInSequence(responses ...Response) *InvocationStub {
+	defer stub.lock()()
+	current := stub
+	for i, response := range responses {
+		if i > 0 {
+			current = current.thenReturn()
+		}
+		current.stdout = response.Stdout
+		current.stderr = response.Stderr
+		current.exitCode = response.ExitCode
+	}
+	return current
+}
+
+// WhenExhausted sets what the stub does once all its responses have been used
+func (stub *InvocationStub) WhenExhausted(behaviour ExhaustedBehaviour) *InvocationStub {
+	defer stub.lock()()
+	stub.first().whenExhausted = behaviour
+	return stub
 }
 
 // WillPrintToStdOut sets up what the mock will print to standard out on invocation
 func (stub *InvocationStub) WillPrintToStdOut(out string) *InvocationStub {
+	defer stub.lock()()
 	stub.stdout = out
 	return stub
 }
 
 // WillPrintToStdErr sets up what the mock will print to standard error on invocation
 func (stub *InvocationStub) WillPrintToStdErr(err string) *InvocationStub {
+	defer stub.lock()()
 	stub.stderr = err
 	return stub
 }
 
 // WillExitWith sets up the exit code of the mock invocation
 func (stub *InvocationStub) WillExitWith(exitCode int) *InvocationStub {
+	defer stub.lock()()
 	stub.exitCode = exitCode
 	return stub
 }
+
+// BlockUntilReleased holds the invocations answered by the stub until the returned gate is released.
+// The mock process keeps running, without printing or exiting, meanwhile
+func (stub *InvocationStub) BlockUntilReleased() *Gate {
+	defer stub.lock()()
+	if stub.gate == nil {
+		stub.gate = newGate()
+	}
+	return stub.gate
+}
+
+func (stub *InvocationStub) thenReturn() *InvocationStub {
+	head := stub.first()
+	next := &InvocationStub{head: head}
+	head.sequence = append(head.sequence, next)
+	return next
+}
+
+// lock locks the mock the stub belongs to, so that the stub can be changed while the mock is being invoked.
+// It returns the function that unlocks it
+func (stub *InvocationStub) lock() func() {
+	mock := stub.first().mock
+	if mock == nil {
+		return func() {}
+	}
+	mock.lock.Lock()
+	return mock.lock.Unlock
+}
+
+func (stub *InvocationStub) first() *InvocationStub {
+	if stub.head != nil {
+		return stub.head
+	}
+	return stub
+}
+
+func (stub *InvocationStub) responses() []*InvocationStub {
+	head := stub.first()
+	return append([]*InvocationStub{head}, head.sequence...)
+}
+
+func (stub *InvocationStub) exhausted() bool {
+	return stub.first().served >= len(stub.responses())
+}
+
+func (stub *InvocationStub) usedUp() bool {
+	return stub.exhausted() && stub.first().whenExhausted == FailWhenExhausted
+}
+
+// selectableByPrecedence tells whether the stub can still answer in MatchByPrecedence mode,
+// where single responses keep answering and only sequences get used up
+func (stub *InvocationStub) selectableByPrecedence() bool {
+	return len(stub.responses()) == 1 || !stub.usedUp()
+}
+
+func (stub *InvocationStub) take() (*InvocationStub, bool) {
+	head := stub.first()
+	responses := stub.responses()
+	if head.served < len(responses) {
+		head.served = head.served + 1
+		return responses[head.served-1], true
+	}
+	if head.whenExhausted == RepeatLastWhenExhausted {
+		return responses[len(responses)-1], true
+	}
+	return nil, false
+}
+
+func (stub *InvocationStub) matches(request invocationRequest) bool {
+	stub = stub.first()
+	if stub.expectedArgs != nil && !reflect.DeepEqual(stub.expectedArgs, request.Args) {
+		return false
+	}
+	return stub.invokedAs == "" || stub.invokedAs == filepath.Base(request.Argv0)
+}
+
+func (stub *InvocationStub) describe() string {
+	stub = stub.first()
+	expectation := "called with any arguments"
+	if stub.expectedArgs != nil {
+		expectation = fmt.Sprintf("called with %v", stub.expectedArgs)
+	}
+	if stub.invokedAs != "" {
+		expectation = fmt.Sprintf("invoked as %s and %s", stub.invokedAs, expectation)
+	}
+	if responses := len(stub.responses()); responses > 1 {
+		return fmt.Sprintf("%s, %d responses in sequence, %d used", expectation, responses, stub.served)
+	}
+	return fmt.Sprintf("%s, exits with %d", expectation, stub.exitCode)
+}
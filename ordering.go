@@ -0,0 +1,77 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"fmt"
+	"sync"
+)
+
+var invocationSequence uint64
+
+var orderingLock sync.Mutex
+
+type ordering struct {
+	stubs []*InvocationStub
+}
+
+// InOrder constrains the stubs, which can belong to different mocks, to be invoked in the order given.
+// An invocation answered by one of the stubs fails if any of the stubs before it hasn't been invoked yet,
+// or if any of the stubs after it has already been invoked
+func InOrder(stubs ...*InvocationStub) {
+	orderingLock.Lock()
+	defer orderingLock.Unlock()
+
+	constraint := &ordering{}
+	for _, stub := range stubs {
+		constraint.stubs = append(constraint.stubs, stub.first())
+	}
+	for _, stub := range constraint.stubs {
+		stub.orderings = append(stub.orderings, constraint)
+	}
+}
+
+func (stub *InvocationStub) orderViolation() string {
+	for _, constraint := range stub.orderings {
+		position := constraint.position(stub)
+		for i, other := range constraint.stubs {
+			if i < position && len(other.answeredAt) == 0 {
+				return fmt.Sprintf("Expected %s to be invoked before %s!", other.name(), stub.name())
+			}
+			if i > position && len(other.answeredAt) > 0 {
+				return fmt.Sprintf("Expected %s to be invoked after %s, but it was invoked first as invocation #%d!", other.name(), stub.name(), other.answeredAt[0])
+			}
+		}
+	}
+	return ""
+}
+
+func (constraint *ordering) position(stub *InvocationStub) int {
+	for i, current := range constraint.stubs {
+		if current == stub {
+			return i
+		}
+	}
+	return -1
+}
+
+func (stub *InvocationStub) name() string {
+	if stub.mock == nil {
+		return fmt.Sprintf("the stub %s", stub.describe())
+	}
+	return fmt.Sprintf("%s %s", stub.mock.displayName(), stub.describe())
+}
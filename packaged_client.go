@@ -68,7 +68,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _clientMainGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x54\xc1\x6e\xdb\x46\x10\x3d\x73\xbf\x62\xca\x43\x41\xa2\x0a\x99\xf8\x52\xc0\x81\x0f\xb2\xac\xa0\x44\x03\xc9\x30\xe5\x06\x41\x90\xc3\x8a\x1c\x52\xd3\x92\xbb\xcc\xee\x52\xb2\x10\x18\xe8\x8f\xf4\xe7\xfa\x25\xc5\x2c\x29\x47\x89\x64\xf7\x24\xee\xec\x9b\x79\x6f\x66\xde\x2a\x4d\x61\xa6\xbb\xbd\xa1\x7a\xe3\x20\x9a\xc5\x70\xf1\xfa\xcd\xaf\xaf\x6e\x0d\x5a\x54\x0e\x6e\x69\xab\x9d\x6c\x20\xd7\x95\xdb\x49\x83\x13\xc8\x54\x91\xc0\xb4\x69\xc0\x67\x58\x60\xa0\xd9\x62\x99\x88\x34\x15\x69\x0a\xab\x0d\x59\xe8\x8c\xae\x8d\x6c\x41\xaa\x12\xdc\x06\x41\x16\x85\x6e\x3b\xa9\xf6\xa4\x6a\x68\xa5\x43\x43\xb2\xb1\x20\x0d\x42\x2b\x4b\x04\xb9\x95\xd4\xc8\x75\x83\xd0\xab\x12\x0d\xd7\xe1\x34\x87\xa6\xb5\xa0\x2b\x7f\xf0\x37\xfe\x6b\xda\xc9\x62\x83\xf0\x9e\x0a\x54\x16\x27\xf0\x07\x1a\x4b\x5a\xc1\x45\xf2\x1a\x22\x06\x84\xe3\xd5\xbf\x7f\xff\x13\xbf\xe5\x62\x7b\xdd\x43\x2b\xf7\xa0\xb4\x83\xde\x22\x38\x16\x59\x51\x83\x80\x0f\x05\x76\x0e\x48\x01\x2b\x6c\x48\xaa\x02\x61\x47\x6e\xe3\x99\xc6\x3a\x87\xde\x3e\x8e\x65\xf4\xda\x49\x52\x20\xa1\xd0\xdd\xfe\xa0\x6f\xc4\x82\x74\x0c\xdd\x38\xd7\x5d\xa6\xe9\x6e\xb7\x4b\xa4\x97\x9b\x68\x53\xa7\xcd\x80\xb1\xe9\xfb\x6c\x36\x5f\xe4\xf3\x57\x17\xc9\xeb\xb1\xf6\xbd\x6a\xd0\xf2\x38\xbf\xf4\x64\xb0\x84\xf5\x1e\x64\xd7\x35\x54\xf8\xb1\x34\x72\x07\xda\x80\xac\x0d\x62\x09\x4e\xb3\xe0\x9d\x21\x47\xaa\x9e\x80\x1d\x97\xc3\x65\x4a\xb2\xce\xd0\xba\x77\x58\x1e\x4d\xec\xa0\x8d\xec\x77\x00\xad\x40\x2a\x08\xa7\x39\x64\x79\x08\xd7\xd3\x3c\xcb\x27\x5c\xe4\x43\xb6\xfa\x6d\x79\xbf\x82\x0f\xd3\xbb\xbb\xe9\x62\x95\xcd\x73\x58\xde\xc1\x6c\xb9\xb8\xc9\x56\xd9\x72\x91\xc3\xf2\x1d\x4c\x17\x1f\xe1\xf7\x6c\x71\x33\x01\x24\xb7\x41\x03\xf8\xd0\x19\xee\x40\x1b\x20\x9e\xe4\x37\x4b\xe4\x88\xdf\xa9\xa8\xf4\xa0\xca\x76\x58\x50\x45\x05\x34\x52\xd5\xbd\xac\x11\x6a\xbd\x45\xa3\xd8\x24\x1d\x9a\x96\x2c\xaf\xd5\xb2\x8b\xb8\x4c\x43\x2d\x39\xe9\x7c\xe8\xa4\xb5\x44\x88\x4e\x16\x7f\x71\x91\x56\x92\x12\x82\xda\x4e\x1b\x07\x91\x08\x42\x54\x85\x2e\x49\xd5\xe9\x9f\x56\xab\x50\x04\x61\xd5\x3a\xfe\x51\xe8\x52\xde\x53\x28\x44\x10\xae\xfb\x8a\x34\x47\xd7\x7b\x87\x96\x3f\xb4\x0d\x45\x2c\xc4\x56\x1a\xa0\x12\x95\xa3\x8a\xd0\x00\x4f\x4f\xd5\x3e\xea\x8d\x6f\xee\x4d\x73\x08\x8a\xaa\x57\x85\xe7\x8f\x62\xf8\x2a\x02\xe6\xcb\xd4\x56\x17\x5e\xf5\x1d\x7e\xe9\xd1\x3a\xb8\xbc\x82\x93\xe0\xd7\xc7\x67\xd0\x49\x56\xc2\xd5\x11\xff\x73\xb0\xa9\xa9\x2d\x5c\x81\xb6\xfe\xeb\xd3\x9b\xcb\xcf\xcf\x21\xe7\x6a\x3b\x00\xe7\x6a\x4b\x46\xab\x28\x16\x22\xb0\x85\x54\x0a\x0d\x6b\xf3\x83\x48\x16\xb8\xcb\x87\x58\xa4\x6d\x92\xbb\x92\x54\x2c\x02\xde\xdc\x08\x4d\xf8\x7a\x68\xf3\x19\x22\x9f\x04\x57\x6c\x63\x54\x65\xf4\x02\x68\xf2\x54\x74\x85\x0f\x2e\x8a\x63\x11\x3c\x0a\x11\xac\xfb\xaa\x1a\x35\xf1\x4e\x58\xd3\xb5\x0f\xe5\x7e\xdc\x51\x18\xc6\x22\xa0\x0a\xd0\x78\x10\x13\x30\x66\xce\xeb\x46\x13\x0d\xe9\x71\x32\x9c\xcf\xf3\xc7\x6f\x7d\xf6\x4f\x57\xa0\xa8\xf1\xbd\x74\x52\x51\x11\xa1\x31\xa3\x08\x83\xb6\xd3\xfe\x6f\x66\xa4\x61\xc7\x24\xb7\xda\xba\x28\x1c\x1f\x79\xf8\xcb\x93\x13\x26\x10\x86\x13\x18\x99\x9f\xc4\xbd\x50\xfe\x47\x55\x03\xd9\x8f\x1e\x19\xa2\x6c\x92\xd3\x76\x6f\x70\x68\xf7\x20\x34\xb9\xd6\xe5\x3e\x4e\x86\x70\xf4\xf3\x79\x82\xff\xef\xbb\x6a\x5d\xf2\xae\x33\xa4\xdc\x68\x00\xdd\xbb\x09\x9c\xaf\x36\x5e\xc7\x67\xb2\xd0\x98\x97\xb2\x06\x3e\x36\xe3\x03\xb9\x93\x15\x8d\x38\xbe\x9b\xe9\x12\x63\xf1\x28\x84\xdb\x77\x78\xfa\x7e\xf8\x05\xf6\x85\xe3\x4e\xb2\x12\x00\x0e\x2f\x32\xf0\xef\x02\x3e\x7d\x3e\x9c\xd9\xfd\xc7\xe7\xc1\xa4\x4f\xe7\x73\x04\xe3\x4a\xbe\x31\x0c\xdd\x1e\x91\x0c\x8d\x1c\x05\x0e\x8a\x81\x94\x13\x8f\xe2\xbf\x00\x00\x00\xff\xff\xc1\x44\x4d\x59\x64\x07\x00\x00")
+var _clientMainGo = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x57\xef\x6e\xdb\x46\x12\xff\x4c\x3e\xc5\x94\x40\x03\xb2\x65\x29\x37\x68\x2f\x80\x02\x7f\x50\x64\xe5\x4e\x68\xe3\x18\x96\x73\x41\x11\x04\xc5\x9a\x1c\x4a\x7b\x26\x77\xd9\xdd\xa5\x64\xc1\x10\x70\x2f\x72\x2f\x77\x4f\x72\x98\xdd\xa5\x48\xd9\x92\x1d\xe0\x0a\x34\xd6\xce\xce\xfc\xe6\xef\xce\x0c\x47\x23\x98\xca\x66\xab\xf8\x72\x65\x20\x9e\x26\xf0\xfa\xec\xe7\x37\x3f\x5d\x29\xd4\x28\x0c\x5c\xf1\xb5\x34\xac\x82\x85\x2c\xcd\x86\x29\x4c\x61\x2e\xf2\x0c\x26\x55\x05\x56\x42\x03\x31\xaa\x35\x16\x59\x38\x1a\x85\xa3\x11\xdc\xac\xb8\x86\x46\xc9\xa5\x62\x35\x30\x51\x80\x59\x21\xb0\x3c\x97\x75\xc3\xc4\x96\x8b\x25\xd4\xcc\xa0\xe2\xac\xd2\xc0\x14\x42\xcd\x0a\x04\xb6\x66\xbc\x62\xb7\x15\x42\x2b\x0a\x54\x84\x43\x62\x06\x55\xad\x41\x96\x16\xc3\xde\xd8\x5f\x93\x86\xe5\x2b\x84\xdf\x79\x8e\x42\x63\x0a\xff\x44\xa5\xb9\x14\xf0\x3a\x3b\x83\x98\x18\x22\x7f\xf5\xdf\x7f\xff\x27\x79\x4b\x60\x5b\xd9\x42\xcd\xb6\x20\xa4\x81\x56\x23\x18\x32\xb2\xe4\x15\x02\xde\xe7\xd8\x18\xe0\x02\xc8\xc2\x8a\x33\x91\x23\x6c\xb8\x59\x81\xe9\x55\x74\xbe\xfd\xe1\x61\xe4\xad\x61\x5c\x00\x83\x5c\x36\xdb\xce\x3e\xcf\x0b\xcc\x10\xeb\xca\x98\x66\x3c\x1a\x6d\x36\x9b\x8c\x59\x73\x33\xa9\x96\xa3\xca\xf1\xe8\xd1\xef\xf3\xe9\xec\x72\x31\xfb\xe9\x75\x76\xe6\xb1\x3f\x89\x0a\x35\x85\xf3\xaf\x96\x2b\x2c\xe0\x76\x0b\xac\x69\x2a\x9e\xdb\xb0\x54\x6c\x03\x52\x01\x5b\x2a\xc4\x02\x8c\x24\x83\x37\x8a\x1b\x2e\x96\x29\x68\x9f\x1c\xd2\x5b\x70\x6d\x14\xbf\x6d\x0d\x16\x83\x88\x75\xb6\x71\x7d\xc0\x20\x05\x30\x01\xd1\x64\x01\xf3\x45\x04\xef\x26\x8b\xf9\x22\x25\x90\xcf\xf3\x9b\x7f\x7c\xfc\x74\x03\x9f\x27\xd7\xd7\x93\xcb\x9b\xf9\x6c\x01\x1f\xaf\x61\xfa\xf1\xf2\x62\x7e\x33\xff\x78\xb9\x80\x8f\xef\x61\x72\xf9\x07\xfc\x36\xbf\xbc\x48\x01\xb9\x59\xa1\x02\xbc\x6f\x14\x79\x20\x15\x70\x8a\x64\x5f\x12\x0b\xc4\x03\x2b\x4a\xa9\xec\x59\x37\x98\xf3\x92\xe7\x50\x31\xb1\x6c\xd9\x12\x61\x29\xd7\xa8\x04\x15\x49\x83\xaa\xe6\x9a\xd2\xaa\xa9\x8a\x08\xa6\xe2\x35\x37\xcc\x58\xd2\x13\xd7\xb2\x30\x6c\x58\x7e\x47\x20\x35\xe3\x22\x0c\x79\xdd\x48\x65\x20\x0e\x83\x08\x45\x2e\x0b\x2e\x96\xa3\x7f\x69\x29\xa2\x30\x88\xca\xda\xd0\x1f\x2e\xdd\xbf\x23\x2e\x5b\xc3\x2b\x3a\x08\x34\x23\x4a\x1d\xfd\x96\x7a\x84\xf7\x98\xd3\x4f\xbd\xd5\x39\xab\xaa\x28\x0c\x83\xe8\xb6\x2d\x9d\xe0\xed\xd6\xa0\xa6\x1f\x52\x47\x61\x12\x86\x6b\xa6\x80\x17\x28\x0c\x2f\x39\x2a\xa0\x3c\x88\xa5\xa5\xda\x17\xa2\x3e\xa9\xaa\x23\x86\x65\x2b\x72\x6b\x68\x9c\xc0\x43\x18\x90\x61\x73\xb1\x96\xb9\x75\xef\x1a\xff\x6a\x51\x1b\x18\x9f\xc3\x13\xe2\xc3\xee\x04\x77\x36\x2f\xe0\x7c\xa0\xff\x14\xdb\x15\x27\x3e\xa9\xb3\xbf\xa3\x69\x78\x11\x27\xa7\x18\x27\x6a\xb9\x3e\x73\xac\x13\xb5\xd4\x5f\xce\xbe\x9e\xe2\xbc\xe0\x2a\x85\x3f\xf7\xa8\x9b\xe7\x41\xf5\x00\xf3\xe7\xf1\x49\xd0\x99\x58\x3b\xc6\x99\x58\x73\x25\xc5\x69\xcc\x85\x51\xc8\x6a\x82\xfd\xf2\xd5\xfd\x9e\x8b\x52\x3e\x84\x41\xc0\x05\x95\x98\x71\xc4\x58\xea\x6c\x61\x0a\x2e\x92\xf4\xc4\x95\x6c\xcd\xc9\x3b\x54\x8a\xee\x76\x61\x18\x68\x02\x49\x01\x95\xa2\x0c\xb9\xda\xc9\xae\x91\x15\x93\xaa\xf2\xdc\x5c\x24\x61\xc0\x4b\xcb\xf3\xdd\x39\x08\x5e\x51\x96\x83\x86\x09\x9e\xc7\x04\x45\x48\x81\xce\x99\x10\x68\x51\x6c\x51\x65\x97\xb8\x59\x38\x5a\x6c\x8b\x8b\x08\x04\x8c\x2a\xb6\x4a\x93\x24\x0c\xe8\xf1\x78\xc1\x8c\x98\x5d\x01\x9d\x0c\x4d\xc1\x05\x9c\x53\x27\x41\x51\xc4\xcf\x30\xa5\x7b\xd0\x1b\xbc\x37\x31\x69\x22\x5f\x1f\x4b\xe8\x46\xd2\x0b\x7e\x54\x99\x8e\x4a\xa5\xd9\x48\x6d\xe2\x68\x14\xa5\x70\x54\x57\x0a\xaf\x8e\x23\x26\xa1\x8d\xd7\xf1\xcb\xec\x8a\x69\x6d\x56\x4a\xb6\xcb\x15\x7c\x77\x0e\x51\x64\x5d\x56\xa8\xdb\xca\xbe\x92\xa6\xbf\x8f\x5f\x86\x48\x41\xfb\x0c\x79\x88\xa7\x4f\x67\x7f\xb1\x07\xb2\x2c\x27\xb0\x7b\xd2\xbc\xa0\x2c\xbb\x18\x38\x88\x28\xa5\x09\xd9\x56\x26\x85\x57\xda\xa8\x36\x37\x0f\xbb\x87\x5d\x12\x06\x01\xde\x73\x13\x7b\x3d\xb3\x7b\x6e\xa6\xb2\x40\x1f\x75\x54\x4a\x2a\x4d\x9e\xd1\x08\xd8\xce\xca\x12\x73\xa3\x4f\xb9\xe6\xaf\xa9\x36\x6a\x93\xbd\x6f\x14\x17\xc6\xd7\xa1\x6c\x4d\x0a\x27\xc4\x7c\xc1\x1f\x91\x42\xa5\x9e\x93\x72\xf5\x6b\xcd\x3f\xc1\xd4\xb9\x63\x1f\x89\x54\x3a\xcb\xb2\x24\xdc\xf9\xae\x67\x05\xd1\x73\x00\x17\xa6\xe3\x82\x2c\xcb\x5c\x7f\xb4\x25\xed\xc3\x48\x9c\x51\x0a\x57\x4a\xe6\xa8\x35\x21\x3f\xcc\x8b\xf1\x20\x5b\x29\x5c\xf1\x62\x3c\xec\x68\x29\xcc\x6c\x00\xc7\x1e\x78\xf7\x38\xf6\x52\x67\xb3\xa1\x15\xbd\x71\x07\xf1\x46\xf7\x17\xbe\x7c\x7d\xcf\x2b\x74\x61\x4e\xe0\xcb\x57\x67\x24\xd9\x48\xcd\xdd\x1b\xdf\x91\xdd\x0b\xfd\x33\x05\x27\x4d\x49\x54\x4c\x2c\xd1\x9f\x35\x89\x75\x72\xf4\xbf\x54\x61\x10\xe8\x0d\x37\xf9\xca\xb3\x64\x93\x9c\xa2\x69\x19\x73\xa6\x11\x22\x9a\xf4\x18\x8d\xc3\x20\x08\xa8\xa3\xec\x9b\xce\x67\xa2\x93\x6d\xb1\x97\xbc\x62\x66\x95\xc2\x97\xaf\xd4\x3d\x3a\xda\x54\x0a\x83\xc2\xe8\x24\x85\xb3\xbf\xfd\xf2\x4b\xb2\x47\x55\x58\xcb\xf5\x01\xac\xd4\xd9\xb5\x25\x0e\xf1\x7a\x81\xfa\xae\xe0\xea\x11\xff\x07\xa2\x51\xe3\x1b\x48\xa4\x70\xf6\xe6\xd7\x5f\x7b\xb9\x7c\x55\xcb\xe2\x91\xdc\x94\x68\x87\x42\xfe\xf0\xc1\x26\x24\x08\x0a\x2c\x59\x5b\x99\x81\x18\x15\xaa\xcd\x6c\x19\x47\xad\xb8\x13\x72\x23\x40\xf3\xa2\x0b\x2d\x7c\xaf\x41\x0a\xf8\x5e\x47\x7b\x2c\x17\xc9\x14\x1e\xb9\xb3\x0b\x83\x23\xdd\x39\xf0\x99\xdc\xb7\x4b\x77\xb6\xe5\xe9\xf4\xda\xb6\x48\x9d\x7b\x17\x06\x0a\x4d\xab\x84\xcf\xfe\xbe\x7e\x6c\xd1\x36\xcc\xac\xfc\xa4\xa7\xd7\x6f\x3b\x1f\x15\x3a\xaa\x92\xe5\xf8\xb0\x23\xa2\x6f\xa4\x03\xaa\x2d\xfa\xdb\xb6\x2c\xfd\x44\xe8\x06\xc0\x3b\x4b\x5a\x58\xb8\x38\x8a\xfa\xc1\x32\x3e\xb7\x8f\x94\x78\x66\xb4\xe1\xd0\xd4\xb0\xbc\x49\xe6\xce\xb1\xd7\x9d\xbc\x7d\x7e\x10\x85\x01\xad\x3c\xdd\xeb\xdd\x4f\x36\x22\x66\x57\xe4\x50\xe4\xb7\xd9\xe8\xc7\xfd\x26\xf3\x23\x39\x99\x42\x14\xa5\xe0\x95\x7e\xc3\xc0\x2b\x90\x9c\x1b\x2a\xcb\xde\xc9\x62\x9b\x4d\x2b\xa9\x31\x4e\xc2\x63\xae\x5d\xa0\x73\xed\x89\x54\x92\xb9\xab\xb8\x0b\xe6\x4b\x6e\xee\x73\x34\x98\x16\xb4\xe0\xb5\xc6\xae\xd8\x5d\xc2\xec\x70\xf0\x6f\x28\x39\x1c\x73\x34\x6e\x1e\xec\x02\x20\x5b\xf3\x42\x92\xb4\x6d\x94\xcf\x33\x85\x41\x2e\xeb\x9a\x3e\x92\xc6\xe7\x40\xa6\x64\x53\x77\x1e\xd8\x95\x0e\x97\x25\xdb\x48\x3b\xa1\xfd\x74\x3f\xbe\x2b\xec\xc1\xfd\x20\xa0\xed\x50\x66\x1f\xda\xca\x70\xdb\x37\xd4\x70\x46\xe8\x6e\x18\x0c\x64\xba\x56\x73\x44\xc6\x4e\x08\xed\x47\x81\x9b\x05\xb6\x9b\x8f\xcf\xe1\x2c\x0c\xbc\xdf\x3d\x14\x53\x26\xee\xeb\xf6\x7c\x90\x20\x5b\x5d\xa3\x7c\xc5\xab\xa2\x6f\xf2\x53\x3a\x7e\x43\x97\xb7\x7c\xe3\xbd\x1e\x2f\x9d\x5d\xf1\xe2\x49\xc7\xf7\x1d\xa4\x63\xfd\xcc\xb8\xb5\x68\x77\xac\x68\x89\x72\xcf\xcd\x8c\x7c\x94\x77\xe4\x09\x35\x80\xf8\x07\xca\x89\x9d\x1b\xb6\x17\x24\x6f\x41\xde\xf9\xbe\xd1\xb9\x7f\xde\x09\x66\x8b\xad\x8e\x93\x2c\xf6\xdf\x0d\x56\xdf\xc2\x30\xd3\xea\xc4\x22\xb8\xdf\x64\x41\xb0\x03\xac\x34\x3a\xa0\x7e\x12\x57\x22\x7e\x31\xf0\xf6\xa1\x26\x8f\x0c\xf8\xf9\xf5\x9b\xc7\x2d\xea\x71\x09\x3f\xb8\xa4\x8f\x7d\xd2\x33\x5f\x90\x49\x0a\x4e\xc1\xd8\x2b\x18\x5c\x74\x03\x7d\x0c\x9d\xaa\xfe\x39\x1d\x6e\xcb\xf6\xa3\xfa\x07\xa9\x33\x1a\x4b\x09\xf4\xfb\x38\x79\xc8\x45\x29\xf7\xed\x85\x38\x33\x0a\x44\x9c\x1c\xcb\x82\x37\x7e\xb0\xd0\xff\xc6\x45\x31\x86\x28\xa7\x66\x51\x44\xb6\x0b\x5b\xc1\x02\xd7\x97\x6d\x55\xed\x81\x6d\x59\x33\xbb\x02\x5d\xb8\xab\xe4\xed\xb0\xf0\x5e\xbd\xa2\x27\xb5\x60\x35\x92\x8d\xb1\x33\xca\x83\x24\xcf\xeb\x2e\x70\x2d\xda\xaa\x8a\x52\xb8\x61\x6a\x89\x66\x0c\xbd\x12\x6f\x50\xed\xdf\x01\xc1\xda\x59\x46\xee\xf9\xf9\xfe\x10\xba\x89\x48\x3c\xaf\x68\x78\xca\x02\x2f\x59\x8d\xc5\x15\x6f\x90\x9c\x3f\x1b\x3f\xa7\xbd\xe1\x0d\x46\xbb\x23\x18\x0b\x99\xdf\xa1\x79\x19\x40\x5b\xbe\xa3\x10\xd3\x15\x53\x17\xb8\xe6\xf9\x37\xd8\x61\xcc\x76\x10\x01\x6d\xef\x5d\x3c\x62\x4a\x6a\x32\xc4\xcf\xe6\xfa\x1a\x97\x6d\xc5\x54\x9c\x3c\x0b\x4a\x92\x2f\xa1\xee\xc2\xd3\xf2\x7e\x21\x88\xfa\xc2\x7c\x02\x31\xa8\xcb\x7e\x85\x33\x56\xc5\xb0\x78\xe8\x8b\xab\xe2\xe2\x2e\xa6\xe7\xb8\xb0\xeb\x74\x19\x47\xa3\x46\xc9\x7c\xa4\xb1\x2a\x47\x65\x31\xfa\x9e\xda\x15\x99\x95\xbd\x2f\xe2\x24\x79\xae\x82\xa3\x68\x68\xb8\x53\x47\x36\x9a\x6d\x83\x07\x6f\xd3\xce\x6a\x72\xbc\xcd\xed\x98\x99\x17\x60\xff\xeb\xf6\x4a\xfa\x72\xa7\x33\x17\x26\x0c\xdc\xd7\x79\x7f\x79\xc1\xd5\x01\x33\x4d\x0c\x3a\xf7\x6b\x29\x7d\x50\x1f\x52\xdc\xfc\x38\xa4\x50\xc8\xf4\xc1\x87\xf4\xde\xd6\x9e\x34\x30\x92\xb2\x37\x50\xeb\xa2\xdd\x1d\x8f\x79\xe9\x17\x9f\x1e\xc1\x4f\xa7\x43\x5f\xfd\xf8\x39\x24\x76\x5d\xa8\x8f\xc2\xe0\xab\xae\x67\xeb\xb5\xcd\x0b\xc7\xe6\x17\x7a\xf0\xfe\xf7\xdb\xfc\xde\xc2\x9e\x34\xf0\xcd\xef\xe1\x3d\x32\xed\xa9\x07\x16\x75\xbb\xf5\x9e\xf0\xa1\x33\xcf\x57\x1a\x9d\x8f\x87\xa1\xad\x86\xaa\xba\x5c\x1f\xa0\x3f\x75\xe4\xff\x88\x55\x67\xc4\x70\xc6\x3e\x31\xa0\x93\x76\xa5\x66\x63\x67\x87\xec\x31\x04\xd2\xf0\xd4\x83\x43\x04\x1f\xfe\x47\x5f\x48\xbb\xf0\x7f\x03\x00\xc2\x4c\xa0\x7b\x75\x16\x00\x00")
 
 func clientMainGoBytes() ([]byte, error) {
 	return bindataRead(
@@ -83,7 +83,7 @@ func clientMainGo() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "client/main.go", size: 1892, mode: os.FileMode(420), modTime: time.Unix(1502124799, 0)}
+	info := bindataFileInfo{name: "client/main.go", size: 5749, mode: os.FileMode(420), modTime: time.Unix(1792418967, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
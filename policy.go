@@ -0,0 +1,97 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type policy struct {
+	forbiddenInArgs []string
+	requiredInEnv   []string
+}
+
+var globalPolicy = &policy{}
+var globalPolicyLock sync.Mutex
+
+// ForbidInArgs fails any invocation of any mock that receives one of the secrets as part of its arguments
+func ForbidInArgs(secrets ...string) {
+	globalPolicyLock.Lock()
+	defer globalPolicyLock.Unlock()
+	globalPolicy.forbidInArgs(secrets...)
+}
+
+// RequireInEnv fails any invocation of any mock that doesn't have all of the environment variables set
+func RequireInEnv(names ...string) {
+	globalPolicyLock.Lock()
+	defer globalPolicyLock.Unlock()
+	globalPolicy.requireInEnv(names...)
+}
+
+// ResetPolicy clears the secrets and environment variables registered with ForbidInArgs and RequireInEnv
+func ResetPolicy() {
+	globalPolicyLock.Lock()
+	defer globalPolicyLock.Unlock()
+	globalPolicy = &policy{}
+}
+
+func (policy *policy) forbidInArgs(secrets ...string) {
+	for _, secret := range secrets {
+		if secret != "" {
+			policy.forbiddenInArgs = append(policy.forbiddenInArgs, secret)
+		}
+	}
+}
+
+func (policy *policy) requireInEnv(names ...string) {
+	policy.requiredInEnv = append(policy.requiredInEnv, names...)
+}
+
+func mergePolicies(policies ...*policy) *policy {
+	merged := &policy{}
+	for _, current := range policies {
+		merged.forbiddenInArgs = append(merged.forbiddenInArgs, current.forbiddenInArgs...)
+		merged.requiredInEnv = append(merged.requiredInEnv, current.requiredInEnv...)
+	}
+	return merged
+}
+
+func (policy *policy) violations(mockName string, args []string, env map[string]string) []string {
+	var violations []string
+	if policy.hasSecretIn(args) {
+		violations = append(violations, fmt.Sprintf("Secret passed as an argument to %s! Call with %v", mockName, args))
+	}
+	for _, name := range policy.requiredInEnv {
+		if _, found := env[name]; !found {
+			violations = append(violations, fmt.Sprintf("Expected %s to be set in the environment of %s! Call with %v", name, mockName, args))
+		}
+	}
+	return violations
+}
+
+func (policy *policy) hasSecretIn(args []string) bool {
+	for _, secret := range policy.forbiddenInArgs {
+		for _, arg := range args {
+			if strings.Contains(arg, secret) {
+				return true
+			}
+		}
+	}
+	return false
+}
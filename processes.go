@@ -0,0 +1,150 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+type process struct {
+	args []string
+	env  map[string]string
+	// child is the real executable started by the process, for spies and recordings
+	child int
+}
+
+func (mock *Mock) started(pid int, args []string, env map[string]string) {
+	if pid <= 0 {
+		return
+	}
+	if mock.processes == nil {
+		mock.processes = map[int]process{}
+	}
+	mock.processes[pid] = process{args: args, env: env}
+}
+
+func (mock *Mock) childStarted(pid, child int) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	if process, found := mock.processes[pid]; found {
+		process.child = child
+		mock.processes[pid] = process
+	}
+}
+
+func (mock *Mock) exited(pid int, errors []string) {
+	mock.lock.Lock()
+	process := mock.processes[pid]
+	delete(mock.processes, pid)
+	mock.lock.Unlock()
+
+	if len(errors) > 0 {
+		mock.fail(fmt.Sprintf("Failed to apply the side effects of the call to %s with %v:\n%s", mock.displayName(), process.args, strings.Join(errors, "\n")), process.env)
+	}
+}
+
+// AssertNoRunningProcesses fails if any process of the mock hasn't exited yet, e.g. because the code under test
+// didn't wait for it. Processes that died without reporting their exit, e.g. killed by a signal, are not reported
+func (mock *Mock) AssertNoRunningProcesses() {
+	pids := mock.runningProcesses()
+	if len(pids) == 0 {
+		return
+	}
+
+	mock.lock.Lock()
+	var running []string
+	for _, pid := range pids {
+		process := mock.processes[pid]
+		line := fmt.Sprintf("pid %d called with %v", pid, process.args)
+		running = append(running, mock.activeRedactions().redact(line, process.env))
+	}
+	mock.lock.Unlock()
+
+	mock.fail(fmt.Sprintf("Expected %s to have no running processes, but found:\n%s", mock.displayName(), strings.Join(running, "\n")), nil)
+}
+
+// KillAll kills the processes of the mock that are still running, along with the real executables started by spies and
+// recordings. Invocations of the killed processes held by a gate stop waiting for it
+func (mock *Mock) KillAll() {
+	for _, pid := range mock.runningProcesses() {
+		mock.lock.Lock()
+		child := mock.processes[pid].child
+		mock.lock.Unlock()
+
+		if child > 0 {
+			syscall.Kill(child, syscall.SIGKILL)
+		}
+		syscall.Kill(pid, syscall.SIGKILL)
+		mock.killed(pid)
+		mock.exited(pid, nil)
+	}
+}
+
+func (mock *Mock) killed(pid int) {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	var running []heldInvocation
+	for _, held := range mock.running {
+		if held.invocation.pid == pid {
+			close(held.killed)
+			continue
+		}
+		running = append(running, held)
+	}
+	mock.running = running
+}
+
+func (mock *Mock) runningProcesses() []int {
+	mock.lock.Lock()
+	defer mock.lock.Unlock()
+	var pids []int
+	for pid := range mock.processes {
+		if !mock.ownsProcess(pid) {
+			delete(mock.processes, pid)
+			continue
+		}
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids
+}
+
+// ownsProcess tells whether the pid is still a process of the mock, rather than a process that reused the pid
+// of one that died without reporting its exit. Without /proc, it only checks that the process exists
+func (mock *Mock) ownsProcess(pid int) bool {
+	if err := syscall.Kill(pid, 0); err == syscall.ESRCH {
+		return false
+	}
+	if _, err := os.Stat("/proc/self/exe"); err != nil {
+		return true
+	}
+
+	executable, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return false
+	}
+	mockPath, err := filepath.EvalSymlinks(mock.Path)
+	if err != nil {
+		mockPath = mock.Path
+	}
+	return strings.TrimSuffix(executable, " (deleted)") == mockPath
+}
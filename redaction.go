@@ -0,0 +1,110 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const redacted = "[REDACTED]"
+
+type redactions struct {
+	values   []string
+	envNames []string
+	patterns []*regexp.Regexp
+}
+
+var globalRedactions = &redactions{}
+var globalRedactionsLock sync.Mutex
+
+// RedactValues hides the values from all the messages produced by any mock and from invocation dumps
+func RedactValues(values ...string) {
+	globalRedactionsLock.Lock()
+	defer globalRedactionsLock.Unlock()
+	globalRedactions.redactValues(values...)
+}
+
+// RedactEnv hides the values of the environment variables, as set at the time of each invocation,
+// from all the messages produced by any mock and from invocation dumps
+func RedactEnv(names ...string) {
+	globalRedactionsLock.Lock()
+	defer globalRedactionsLock.Unlock()
+	globalRedactions.redactEnv(names...)
+}
+
+// RedactPatterns hides anything matching the patterns from all the messages produced by any mock and from invocation dumps
+func RedactPatterns(patterns ...*regexp.Regexp) {
+	globalRedactionsLock.Lock()
+	defer globalRedactionsLock.Unlock()
+	globalRedactions.redactPatterns(patterns...)
+}
+
+// ResetRedactions clears the values, environment variables and patterns registered for all mocks
+func ResetRedactions() {
+	globalRedactionsLock.Lock()
+	defer globalRedactionsLock.Unlock()
+	globalRedactions = &redactions{}
+}
+
+func (redactions *redactions) redactValues(values ...string) {
+	for _, value := range values {
+		if value != "" {
+			redactions.values = append(redactions.values, value)
+		}
+	}
+}
+
+func (redactions *redactions) redactEnv(names ...string) {
+	redactions.envNames = append(redactions.envNames, names...)
+}
+
+func (redactions *redactions) redactPatterns(patterns ...*regexp.Regexp) {
+	redactions.patterns = append(redactions.patterns, patterns...)
+}
+
+func mergeRedactions(all ...*redactions) *redactions {
+	merged := &redactions{}
+	for _, current := range all {
+		merged.values = append(merged.values, current.values...)
+		merged.envNames = append(merged.envNames, current.envNames...)
+		merged.patterns = append(merged.patterns, current.patterns...)
+	}
+	return merged
+}
+
+func (redactions *redactions) redact(text string, env map[string]string) string {
+	values := append([]string{}, redactions.values...)
+	for _, name := range redactions.envNames {
+		if value := env[name]; value != "" {
+			values = append(values, value)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+
+	for _, value := range values {
+		text = strings.Replace(text, value, redacted, -1)
+	}
+	for _, pattern := range redactions.patterns {
+		text = pattern.ReplaceAllString(text, redacted)
+	}
+	return text
+}
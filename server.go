@@ -20,9 +20,11 @@ import (
 	"encoding/json"
 	"net"
 	"net/http"
+	"sync"
 )
 
 type server struct {
+	lock  sync.RWMutex
 	mocks map[string]*Mock
 	*http.Server
 	listener net.Listener
@@ -47,16 +49,50 @@ func (server *server) start() {
 }
 
 type invocationRequest struct {
-	Id    string
-	Args  []string
-	Env   []string
-	Stdin []string
+	Id      string
+	Pid     int
+	Argv0   string
+	Dir     string
+	Args    []string
+	Env     []string
+	Stdin   []string
+	Streams []Stream
+
+	files map[string]string
+	trees map[string][]FileEntry
 }
 
 type invocationResponse struct {
-	Stdout   string
-	Stderr   string
-	ExitCode int
+	Stdout       string
+	Stderr       string
+	ExitCode     int
+	Passthrough  string
+	InvocationId int
+	Effects      []fileEffect
+
+	gate     *Gate
+	sequence uint64
+	killed   chan struct{}
+}
+
+type invocationResult struct {
+	Id           string
+	InvocationId int
+	Stdout       string
+	Stderr       string
+	ExitCode     int
+}
+
+type processChild struct {
+	Id    string
+	Pid   int
+	Child int
+}
+
+type processExit struct {
+	Id     string
+	Pid    int
+	Errors []string
 }
 
 func newInvocationResponse(exitCode int, stdout, stderr string) invocationResponse {
@@ -68,13 +104,58 @@ func newInvocationResponse(exitCode int, stdout, stderr string) invocationRespon
 }
 
 func (server *server) serve(resp http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/result" {
+		server.serveResult(resp, req)
+		return
+	}
+	if req.URL.Path == "/exit" {
+		server.serveExit(resp, req)
+		return
+	}
+	if req.URL.Path == "/child" {
+		server.serveChild(resp, req)
+		return
+	}
+
 	invocationRequest := invocationRequest{}
 	json.NewDecoder(req.Body).Decode(&invocationRequest)
-	currentMock := server.mocks[invocationRequest.Id]
-	invocationResponse := newInvocationResponse(currentMock.invoke(invocationRequest.Args, invocationRequest.Env, invocationRequest.Stdin))
+	currentMock := server.mock(invocationRequest.Id)
+	invocationResponse := currentMock.invoke(invocationRequest)
 	json.NewEncoder(resp).Encode(invocationResponse)
 }
 
+func (server *server) serveResult(resp http.ResponseWriter, req *http.Request) {
+	invocationResult := invocationResult{}
+	json.NewDecoder(req.Body).Decode(&invocationResult)
+	currentMock := server.mock(invocationResult.Id)
+	currentMock.complete(invocationResult)
+	json.NewEncoder(resp).Encode(struct{}{})
+}
+
+func (server *server) serveExit(resp http.ResponseWriter, req *http.Request) {
+	processExit := processExit{}
+	json.NewDecoder(req.Body).Decode(&processExit)
+	currentMock := server.mock(processExit.Id)
+	currentMock.exited(processExit.Pid, processExit.Errors)
+	json.NewEncoder(resp).Encode(struct{}{})
+}
+
+func (server *server) serveChild(resp http.ResponseWriter, req *http.Request) {
+	processChild := processChild{}
+	json.NewDecoder(req.Body).Decode(&processChild)
+	currentMock := server.mock(processChild.Id)
+	currentMock.childStarted(processChild.Pid, processChild.Child)
+	json.NewEncoder(resp).Encode(struct{}{})
+}
+
 func (server *server) monitor(mock *Mock) {
+	server.lock.Lock()
+	defer server.lock.Unlock()
 	server.mocks[mock.identifier] = mock
 }
+
+func (server *server) mock(identifier string) *Mock {
+	server.lock.RLock()
+	defer server.lock.RUnlock()
+	return server.mocks[identifier]
+}
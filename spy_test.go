@@ -0,0 +1,118 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pivotal-cf/go-binmock"
+)
+
+var _ = Describe("Spy", func() {
+	var spy *binmock.Mock
+	var currentMockFailure *mockFailure
+	var workDir string
+
+	BeforeEach(func() {
+		currentMockFailure = &mockFailure{}
+
+		var err error
+		workDir, err = ioutil.TempDir("", "binmock-spy")
+		Expect(err).NotTo(HaveOccurred())
+
+		realExecutable := writeScript(workDir, "real", `echo "real $@ $GREETING"; read line; echo "read $line" >&2; exit 7`)
+		spy = binmock.NewSpy(realExecutable, currentMockFailure.Fail)
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(workDir)
+	})
+
+	It("runs the real executable with the same arguments, environment and stdin", func() {
+		command := MakeCommand(spy.Path, "one", "two")
+		command.Env = []string{"GREETING=hello"}
+		command.Stdin = bytes.NewBufferString("input\n")
+		session := StartCommand(command)
+
+		Expect(session).To(gexec.Exit(7))
+		Expect(session.Out).To(gbytes.Say("real one two hello"))
+		Expect(session.Err).To(gbytes.Say("read input"))
+	})
+
+	It("records the invocation with the outcome of the real executable", func() {
+		RunCommand(spy.Path, "one")
+
+		Expect(spy.Invocations()).To(HaveLen(1))
+		Expect(spy.Invocations()[0].Args()).To(Equal([]string{"one"}))
+		Expect(spy.Invocations()[0].ExitCode()).To(Equal(7))
+		Expect(spy.Invocations()[0].Stdout()).To(Equal("real one \n"))
+		Expect(spy.Invocations()[0].Stderr()).To(Equal("read \n"))
+	})
+
+	It("lets stubs override the calls they match", func() {
+		spy.WhenCalledWith("two").WillPrintToStdOut("stubbed").WillExitWith(0)
+
+		first := RunCommand(spy.Path, "one")
+		second := RunCommand(spy.Path, "two")
+		third := RunCommand(spy.Path, "two")
+
+		Expect(first.Out).To(gbytes.Say("real one"))
+		Expect(second).To(gexec.Exit(0))
+		Expect(second.Out).To(gbytes.Say("stubbed"))
+		Expect(third.Out).To(gbytes.Say("real two"))
+		Expect(spy.Invocations()[1].Stdout()).To(Equal("stubbed"))
+		Expect(currentMockFailure.called).To(BeFalse())
+	})
+	It("kills the real executable along with the spy", func() {
+		pidFile := filepath.Join(workDir, "pid")
+		sleeper := writeScript(workDir, "sleeper", `echo $$ > `+pidFile+`; exec sleep 100`)
+		sleeperSpy := binmock.NewSpy(sleeper, currentMockFailure.Fail)
+		session, err := gexec.Start(MakeCommand(sleeperSpy.Path), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() string {
+			pid, _ := ioutil.ReadFile(pidFile)
+			return strings.TrimSpace(string(pid))
+		}, 10*time.Second).ShouldNot(BeEmpty())
+		pid, err := ioutil.ReadFile(pidFile)
+		Expect(err).NotTo(HaveOccurred())
+
+		sleeperSpy.KillAll()
+
+		Eventually(session, 10*time.Second).Should(gexec.Exit())
+		Eventually(func() bool { return isRunning(strings.TrimSpace(string(pid))) }, 10*time.Second).Should(BeFalse())
+	})
+})
+
+// isRunning tells whether the process is alive, counting zombies that nobody reaps as dead
+func isRunning(pid string) bool {
+	stat, err := ioutil.ReadFile(filepath.Join("/proc", pid, "stat"))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[strings.LastIndex(string(stat), ")")+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
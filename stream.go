@@ -0,0 +1,37 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+// StreamKind describes what one of the standard streams of the mock was connected to
+type StreamKind string
+
+const (
+	StreamPipe    StreamKind = "pipe"
+	StreamTTY     StreamKind = "tty"
+	StreamFile    StreamKind = "file"
+	StreamDevNull StreamKind = "devnull"
+	StreamSocket  StreamKind = "socket"
+	StreamClosed  StreamKind = "closed"
+	StreamUnknown StreamKind = "unknown"
+)
+
+// Stream represents a standard stream of the mock at the time of invocation.
+// Target is the path the stream was connected to, when it can be determined (regular files, terminals and /dev/null)
+type Stream struct {
+	Kind   StreamKind
+	Target string
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Toolbox is a directory of named mocks that can be put on the PATH of the system under test
+type Toolbox struct {
+	Dir         string
+	failHandler FailHandler
+	mocks       map[string]*Mock
+
+	hermetic     bool
+	originalPath string
+	unmocked     *Mock
+
+	lock        sync.Mutex
+	invocations []ToolInvocation
+}
+
+// ToolInvocation is an invocation of one of the mocks of a toolbox
+type ToolInvocation struct {
+	Tool string
+	Invocation
+}
+
+// Creates a new toolbox containing a mock for each of the names
+func NewToolbox(failHandler FailHandler, names ...string) *Toolbox {
+	dir, err := ioutil.TempDir("", "binmock_toolbox")
+	if err != nil {
+		failHandler(fmt.Sprintf("cant create toolbox directory %v", err))
+		return nil
+	}
+
+	toolbox := &Toolbox{Dir: dir, failHandler: failHandler, mocks: map[string]*Mock{}}
+	for _, name := range names {
+		toolbox.Add(name)
+	}
+	return toolbox
+}
+
+// Creates a new toolbox containing a mock for each of the names, and a shim for every other executable found on path.
+// Invoking any of the shims fails with the name and arguments of the command.
+// The PATH of a hermetic toolbox only contains the toolbox directory
+func NewHermeticToolbox(failHandler FailHandler, path string, names ...string) *Toolbox {
+	toolbox := NewToolbox(failHandler, names...)
+	if toolbox == nil {
+		return nil
+	}
+	toolbox.hermetic = true
+	toolbox.originalPath = path
+
+	toolbox.Forbid(executablesOn(path)...)
+	return toolbox
+}
+
+// Add creates a new named mock in the toolbox directory
+func (toolbox *Toolbox) Add(name string) *Mock {
+	mock := NewNamedBinMock(name, toolbox.failHandler)
+	if mock == nil {
+		return nil
+	}
+
+	if !toolbox.link(mock.Path, name) {
+		return nil
+	}
+
+	mock.onInvocation(func(invocation Invocation) {
+		toolbox.lock.Lock()
+		defer toolbox.lock.Unlock()
+		toolbox.invocations = append(toolbox.invocations, ToolInvocation{Tool: name, Invocation: invocation})
+	})
+	toolbox.mocks[name] = mock
+	return mock
+}
+
+// Forbid adds shims for the names that fail when invoked, showing the name and arguments of the command.
+// Names that already have a mock in the toolbox are left untouched
+func (toolbox *Toolbox) Forbid(names ...string) {
+	if toolbox.unmocked == nil {
+		toolbox.unmocked = newBinMock("unmocked", toolbox.failHandler)
+		toolbox.unmocked.lock.Lock()
+		toolbox.unmocked.unmocked = true
+		toolbox.unmocked.lock.Unlock()
+	}
+
+	for _, name := range names {
+		if _, found := toolbox.mocks[name]; !found {
+			toolbox.link(toolbox.unmocked.Path, name)
+		}
+	}
+}
+
+// Allow makes the real executables with the names available in the toolbox, replacing their shims.
+// The executables are looked up on the path given to NewHermeticToolbox, or on the PATH of the test process
+func (toolbox *Toolbox) Allow(names ...string) {
+	path := toolbox.originalPath
+	if !toolbox.hermetic {
+		path = os.Getenv("PATH")
+	}
+
+	for _, name := range names {
+		realPath, found := lookPath(name, path)
+		if !found {
+			toolbox.failHandler(fmt.Sprintf("cant find %s on %s", name, path))
+			continue
+		}
+		toolbox.link(realPath, name)
+	}
+}
+
+// Cleanup removes the toolbox directory, with the mocks, shims and links in it, and cleans up the mocks of the toolbox
+func (toolbox *Toolbox) Cleanup() {
+	for _, mock := range toolbox.mocks {
+		mock.Cleanup()
+	}
+	if toolbox.unmocked != nil {
+		toolbox.unmocked.Cleanup()
+	}
+	if err := os.RemoveAll(toolbox.Dir); err != nil {
+		toolbox.failHandler(fmt.Sprintf("cant remove toolbox directory %s %v", toolbox.Dir, err))
+	}
+}
+
+// Mock returns the mock with the given name in the toolbox
+func (toolbox *Toolbox) Mock(name string) *Mock {
+	mock, found := toolbox.mocks[name]
+	if !found {
+		toolbox.failHandler(fmt.Sprintf("no mock called %s in the toolbox", name))
+	}
+	return mock
+}
+
+// Path returns a PATH with the toolbox directory ahead of the current PATH of the test process.
+// For a hermetic toolbox the PATH only contains the toolbox directory
+func (toolbox *Toolbox) Path() string {
+	if toolbox.hermetic {
+		return toolbox.Dir
+	}
+	return toolbox.Dir + string(os.PathListSeparator) + os.Getenv("PATH")
+}
+
+// Env returns the environment of the test process with the PATH replaced by the one of the toolbox,
+// ready to be used as the Env of an exec.Cmd
+func (toolbox *Toolbox) Env() []string {
+	env := []string{}
+	for _, envVar := range os.Environ() {
+		if !strings.HasPrefix(envVar, "PATH=") {
+			env = append(env, envVar)
+		}
+	}
+	return append(env, "PATH="+toolbox.Path())
+}
+
+// Invocations returns the invocations of all the mocks of the toolbox till now, in the order they happened
+func (toolbox *Toolbox) Invocations() []ToolInvocation {
+	toolbox.lock.Lock()
+	defer toolbox.lock.Unlock()
+	return append([]ToolInvocation{}, toolbox.invocations...)
+}
+
+func (toolbox *Toolbox) link(target, name string) bool {
+	link := filepath.Join(toolbox.Dir, name)
+	if err := os.Remove(link); err != nil && !os.IsNotExist(err) {
+		toolbox.failHandler(fmt.Sprintf("cant replace %s in the toolbox %v", name, err))
+		return false
+	}
+	if err := os.Symlink(target, link); err != nil {
+		toolbox.failHandler(fmt.Sprintf("cant add %s to the toolbox %v", name, err))
+		return false
+	}
+	return true
+}
+
+func executablesOn(path string) []string {
+	found := map[string]bool{}
+	names := []string{}
+	for _, dir := range filepath.SplitList(path) {
+		entries, err := ioutil.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if found[entry.Name()] {
+				continue
+			}
+			if isExecutable(filepath.Join(dir, entry.Name())) {
+				found[entry.Name()] = true
+				names = append(names, entry.Name())
+			}
+		}
+	}
+	return names
+}
+
+func lookPath(name, path string) (string, bool) {
+	for _, dir := range filepath.SplitList(path) {
+		if candidate := filepath.Join(dir, name); isExecutable(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.Mode().IsRegular() && info.Mode()&0111 != 0
+}
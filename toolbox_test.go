@@ -0,0 +1,147 @@
+// Copyright (C) 2017-Present Pivotal Software, Inc. All rights reserved.
+//
+// This program and the accompanying materials are made available under
+// the terms of the under the Apache License, Version 2.0 (the "License”);
+// you may not use this file except in compliance with the License.
+//
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binmock_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/gexec"
+
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pivotal-cf/go-binmock"
+)
+
+var _ = Describe("Toolbox", func() {
+	var toolbox *binmock.Toolbox
+	var currentMockFailure *mockFailure
+
+	BeforeEach(func() {
+		currentMockFailure = &mockFailure{}
+		toolbox = binmock.NewToolbox(currentMockFailure.Fail, "git", "tar")
+	})
+
+	AfterEach(func() {
+		toolbox.Cleanup()
+	})
+
+	It("removes the toolbox directory on cleanup", func() {
+		toolbox.Cleanup()
+
+		Expect(toolbox.Dir).NotTo(BeADirectory())
+		Expect(currentMockFailure.called).To(BeFalse())
+	})
+
+	It("puts the mocks in the toolbox directory", func() {
+		Expect(filepath.Join(toolbox.Dir, "git")).To(BeAnExistingFile())
+		Expect(filepath.Join(toolbox.Dir, "tar")).To(BeAnExistingFile())
+	})
+
+	It("makes the mocks available by name on the PATH", func() {
+		toolbox.Mock("git").WhenCalledWith("status").WillPrintToStdOut("clean")
+		toolbox.Mock("tar").WhenCalledWith("-cf", "x.tar").WillExitWith(0)
+
+		command := exec.Command("sh", "-c", "git status && tar -cf x.tar")
+		command.Env = toolbox.Env()
+
+		Expect(StartCommand(command).Out).To(gbytes.Say("clean"))
+		Expect(currentMockFailure.called).To(BeFalse())
+	})
+
+	It("allows mocks to be added later", func() {
+		toolbox.Add("ssh").WhenCalled().WillPrintToStdOut("connected")
+
+		command := exec.Command("sh", "-c", "ssh host")
+		command.Env = []string{"PATH=" + toolbox.Path()}
+
+		Expect(StartCommand(command).Out).To(gbytes.Say("connected"))
+	})
+
+	It("keeps the invocations of all the mocks in order", func() {
+		toolbox.Mock("git").WhenCalled()
+		toolbox.Mock("tar").WhenCalled()
+		toolbox.Mock("git").WhenCalled()
+
+		RunCommand(filepath.Join(toolbox.Dir, "git"), "clone")
+		RunCommand(filepath.Join(toolbox.Dir, "tar"), "-xf")
+		RunCommand(filepath.Join(toolbox.Dir, "git"), "log")
+
+		invocations := toolbox.Invocations()
+		Expect(invocations).To(HaveLen(3))
+		Expect(invocations[0].Tool).To(Equal("git"))
+		Expect(invocations[0].Args()).To(Equal([]string{"clone"}))
+		Expect(invocations[1].Tool).To(Equal("tar"))
+		Expect(invocations[2].Tool).To(Equal("git"))
+		Expect(invocations[2].Args()).To(Equal([]string{"log"}))
+	})
+
+	It("fails when asked for a mock it doesn't contain", func() {
+		toolbox.Mock("ssh")
+
+		Expect(currentMockFailure.lastMessage).To(ContainSubstring("no mock called ssh in the toolbox"))
+	})
+
+	Describe("when it is hermetic", func() {
+		BeforeEach(func() {
+			toolbox.Cleanup()
+			toolbox = binmock.NewHermeticToolbox(currentMockFailure.Fail, os.Getenv("PATH"), "git")
+			toolbox.Allow("sh")
+		})
+
+		It("only puts the toolbox directory on the PATH", func() {
+			Expect(toolbox.Path()).To(Equal(toolbox.Dir))
+		})
+
+		It("runs the mocks", func() {
+			toolbox.Mock("git").WhenCalled().WillPrintToStdOut("mocked git")
+
+			command := exec.Command("sh", "-c", "git status")
+			command.Env = []string{"PATH=" + toolbox.Path()}
+
+			Expect(StartCommand(command).Out).To(gbytes.Say("mocked git"))
+		})
+
+		It("runs the allowed executables", func() {
+			command := exec.Command(filepath.Join(toolbox.Dir, "sh"), "-c", "echo allowed")
+
+			Expect(StartCommand(command).Out).To(gbytes.Say("allowed"))
+		})
+
+		It("fails when a command that is not mocked is invoked", func() {
+			command := exec.Command("sh", "-c", "ls -la /")
+			command.Env = []string{"PATH=" + toolbox.Path()}
+
+			Expect(StartCommand(command)).To(gexec.Exit(127))
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Unexpected call to ls, which is not mocked! Call with [-la /]"))
+		})
+	})
+
+	Describe("when commands are forbidden", func() {
+		It("fails when one of them is invoked", func() {
+			toolbox.Forbid("curl")
+
+			command := exec.Command("sh", "-c", "curl example.com")
+			command.Env = toolbox.Env()
+			StartCommand(command)
+
+			Expect(currentMockFailure.lastMessage).To(ContainSubstring("Unexpected call to curl, which is not mocked! Call with [example.com]"))
+		})
+	})
+})